@@ -19,13 +19,19 @@ package v1alpha1
 import (
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/kubesphere/kubekey/pkg/util"
 	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -40,6 +46,7 @@ type ClusterSpec struct {
 	Hosts                []HostCfg            `yaml:"hosts" json:"hosts,omitempty"`
 	RoleGroups           RoleGroups           `yaml:"roleGroups" json:"roleGroups,omitempty"`
 	ControlPlaneEndpoint ControlPlaneEndpoint `yaml:"controlPlaneEndpoint" json:"controlPlaneEndpoint,omitempty"`
+	Etcd                 EtcdCluster          `yaml:"etcd" json:"etcd,omitempty"`
 	Kubernetes           Kubernetes           `yaml:"kubernetes" json:"kubernetes,omitempty"`
 	Network              NetworkConfig        `yaml:"network" json:"network,omitempty"`
 	Registry             RegistryConfig       `yaml:"registry" json:"registry,omitempty"`
@@ -122,6 +129,7 @@ type HostCfg struct {
 	Name            string            `yaml:"name,omitempty" json:"name,omitempty"`
 	Address         string            `yaml:"address,omitempty" json:"address,omitempty"`
 	InternalAddress string            `yaml:"internalAddress,omitempty" json:"internalAddress,omitempty"`
+	InternalIPv6    string            `yaml:"internalIPv6,omitempty" json:"internalIPv6,omitempty"`
 	Port            int               `yaml:"port,omitempty" json:"port,omitempty"`
 	User            string            `yaml:"user,omitempty" json:"user,omitempty"`
 	Password        string            `yaml:"password,omitempty" json:"password,omitempty"`
@@ -129,30 +137,41 @@ type HostCfg struct {
 	PrivateKeyPath  string            `yaml:"privateKeyPath,omitempty" json:"privateKeyPath,omitempty"`
 	Arch            string            `yaml:"arch,omitempty" json:"arch,omitempty"`
 	Labels          map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Taints          []corev1.Taint    `yaml:"taints,omitempty" json:"taints,omitempty"`
+	Kubelet         *KubeletConfig    `yaml:"kubelet,omitempty" json:"kubelet,omitempty"`
 	ID              int               `json:"-"`
 	IsEtcd          bool              `json:"-"`
 	IsMaster        bool              `json:"-"`
 	IsWorker        bool              `json:"-"`
+	IsLoadBalancer  bool              `json:"-"`
 }
 
 type RoleGroups struct {
-	Etcd   []string `yaml:"etcd" json:"etcd,omitempty"`
-	Master []string `yaml:"master" json:"master,omitempty"`
-	Worker []string `yaml:"worker" json:"worker,omitempty"`
+	Etcd         []string `yaml:"etcd" json:"etcd,omitempty"`
+	Master       []string `yaml:"master" json:"master,omitempty"`
+	Worker       []string `yaml:"worker" json:"worker,omitempty"`
+	LoadBalancer []string `yaml:"loadbalancer" json:"loadbalancer,omitempty"`
 }
 
 type HostGroups struct {
-	All    []HostCfg
-	Etcd   []HostCfg
-	Master []HostCfg
-	Worker []HostCfg
-	K8s    []HostCfg
+	All          []HostCfg
+	Etcd         []HostCfg
+	Master       []HostCfg
+	Worker       []HostCfg
+	K8s          []HostCfg
+	LoadBalancer []HostCfg
 }
 
 type ControlPlaneEndpoint struct {
-	Domain  string `yaml:"domain" json:"domain,omitempty"`
-	Address string `yaml:"address" json:"address,omitempty"`
-	Port    int    `yaml:"port" json:"port,omitempty"`
+	Domain               string `yaml:"domain" json:"domain,omitempty"`
+	Address              string `yaml:"address" json:"address,omitempty"`
+	Port                 int    `yaml:"port" json:"port,omitempty"`
+	InternalLoadbalancer string `yaml:"internalLoadbalancer" json:"internalLoadbalancer,omitempty"`
+}
+
+type EtcdCluster struct {
+	Type     string       `yaml:"type" json:"type,omitempty"`
+	External ExternalEtcd `yaml:"external" json:"external,omitempty"`
 }
 
 type RegistryConfig struct {
@@ -168,10 +187,10 @@ type KubeSphere struct {
 }
 
 type ExternalEtcd struct {
-	Endpoints []string
-	CaFile    string
-	CertFile  string
-	KeyFile   string
+	Endpoints []string `yaml:"endpoints" json:"endpoints,omitempty"`
+	CaFile    string   `yaml:"caFile" json:"caFile,omitempty"`
+	CertFile  string   `yaml:"certFile" json:"certFile,omitempty"`
+	KeyFile   string   `yaml:"keyFile" json:"keyFile,omitempty"`
 }
 
 func (cfg *ClusterSpec) GenerateCertSANs() []string {
@@ -191,9 +210,15 @@ func (cfg *ClusterSpec) GenerateCertSANs() []string {
 		if host.InternalAddress != host.Address && host.InternalAddress != cfg.ControlPlaneEndpoint.Address {
 			extraCertSANs = append(extraCertSANs, host.InternalAddress)
 		}
+		if host.InternalIPv6 != "" {
+			extraCertSANs = append(extraCertSANs, host.InternalIPv6)
+		}
 	}
 
-	extraCertSANs = append(extraCertSANs, util.ParseIp(cfg.Network.KubeServiceCIDR)[0])
+	// the first IP of every service CIDR is the IP of the kubernetes service
+	for _, cidr := range cfg.Network.ServiceCIDRs() {
+		extraCertSANs = append(extraCertSANs, util.GetIPFromCIDR(cidr, 1))
+	}
 
 	defaultCertSANs = append(defaultCertSANs, extraCertSANs...)
 
@@ -212,10 +237,24 @@ func (cfg *ClusterSpec) GroupHosts(logger *log.Logger) (*HostGroups, error) {
 		hostList[host.Name] = host.Name
 	}
 
-	etcdGroup, masterGroup, workerGroup, err := cfg.ParseRolesList(hostList, logger)
+	etcdGroup, masterGroup, workerGroup, loadBalancerGroup, err := cfg.ParseRolesList(hostList, logger)
 	if err != nil {
 		return nil, err
 	}
+	switch cfg.Etcd.Type {
+	case EtcdTypeKubeKey:
+	case EtcdTypeKubeadm:
+		// the stacked etcd of kubeadm runs on every master, roleGroups.etcd is ignored
+		etcdGroup = masterGroup
+	case EtcdTypeExternal:
+		// the external etcd is not managed by kk, roleGroups.etcd is ignored
+		etcdGroup = nil
+		if err := cfg.Etcd.External.Validate(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New(fmt.Sprintf("Unsupported etcd type: %s", cfg.Etcd.Type))
+	}
 	for index, host := range cfg.Hosts {
 		host.ID = index
 		if len(etcdGroup) > 0 {
@@ -245,6 +284,13 @@ func (cfg *ClusterSpec) GroupHosts(logger *log.Logger) (*HostGroups, error) {
 			}
 		}
 
+		for _, hostName := range loadBalancerGroup {
+			if host.Name == hostName {
+				host.IsLoadBalancer = true
+				break
+			}
+		}
+
 		if host.IsEtcd {
 			clusterHostsGroups.Etcd = append(clusterHostsGroups.Etcd, host)
 		}
@@ -257,6 +303,9 @@ func (cfg *ClusterSpec) GroupHosts(logger *log.Logger) (*HostGroups, error) {
 		if host.IsMaster || host.IsWorker {
 			clusterHostsGroups.K8s = append(clusterHostsGroups.K8s, host)
 		}
+		if host.IsLoadBalancer {
+			clusterHostsGroups.LoadBalancer = append(clusterHostsGroups.LoadBalancer, host)
+		}
 		clusterHostsGroups.All = append(clusterHostsGroups.All, host)
 	}
 
@@ -264,7 +313,7 @@ func (cfg *ClusterSpec) GroupHosts(logger *log.Logger) (*HostGroups, error) {
 	if len(masterGroup) == 0 {
 		logger.Fatal(errors.New("The number of master cannot be 0."))
 	}
-	if len(etcdGroup) == 0 {
+	if len(etcdGroup) == 0 && cfg.Etcd.Type != EtcdTypeExternal {
 		logger.Fatal(errors.New("The number of etcd cannot be 0."))
 	}
 
@@ -277,18 +326,451 @@ func (cfg *ClusterSpec) GroupHosts(logger *log.Logger) (*HostGroups, error) {
 	if len(workerGroup) != len(clusterHostsGroups.Worker) {
 		return nil, errors.New("Incorrect nodeName under roleGroups/work in the configuration file, Please check before installing.")
 	}
+	if len(loadBalancerGroup) != len(clusterHostsGroups.LoadBalancer) {
+		return nil, errors.New("Incorrect nodeName under roleGroups/loadbalancer in the configuration file, Please check before installing.")
+	}
+	for _, host := range clusterHostsGroups.LoadBalancer {
+		// haproxy listens on the port of kube-apiserver
+		if host.IsMaster {
+			return nil, errors.New(fmt.Sprintf("The master %s can not be a load balancer, Please check before installing.", host.Name))
+		}
+	}
 
 	return &clusterHostsGroups, nil
 }
 
+// EtcdClientCerts returns the CA, the client certificate and key used on the given master to access etcd.
+func (cfg *ClusterSpec) EtcdClientCerts(master string) (caFile, certFile, keyFile string) {
+	switch cfg.Etcd.Type {
+	case EtcdTypeKubeadm:
+		return "/etc/kubernetes/pki/etcd/ca.crt", "/etc/kubernetes/pki/apiserver-etcd-client.crt", "/etc/kubernetes/pki/apiserver-etcd-client.key"
+	case EtcdTypeExternal:
+		return "/etc/ssl/etcd/ssl/external-ca.pem", "/etc/ssl/etcd/ssl/external-client.pem", "/etc/ssl/etcd/ssl/external-client-key.pem"
+	}
+	return "/etc/ssl/etcd/ssl/ca.pem", fmt.Sprintf("/etc/ssl/etcd/ssl/node-%s.pem", master), fmt.Sprintf("/etc/ssl/etcd/ssl/node-%s-key.pem", master)
+}
+
+// EtcdEndpoints returns the client URLs of the etcd members.
+func (cfg *ClusterSpec) EtcdEndpoints(etcdNodes []HostCfg) []string {
+	if cfg.Etcd.Type == EtcdTypeExternal {
+		return cfg.Etcd.External.Endpoints
+	}
+	var endpoints []string
+	for _, host := range etcdNodes {
+		endpoints = append(endpoints, fmt.Sprintf("https://%s:%s", host.InternalAddress, DefaultEtcdPort))
+	}
+	return endpoints
+}
+
+// Validate checks that the endpoints and the client certificate of the external etcd are given.
+func (e *ExternalEtcd) Validate() error {
+	if len(e.Endpoints) == 0 {
+		return errors.New("The endpoints of the external etcd are required.")
+	}
+	for _, endpoint := range e.Endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+			return errors.New(fmt.Sprintf("Invalid endpoint of the external etcd: %s, it should be like https://192.168.0.10:2379", endpoint))
+		}
+	}
+	if e.CaFile == "" || e.CertFile == "" || e.KeyFile == "" {
+		return errors.New("The caFile, certFile and keyFile of the external etcd are required.")
+	}
+	return nil
+}
+
 func (cfg *ClusterSpec) ClusterIP() string {
-	return util.ParseIp(cfg.Network.KubeServiceCIDR)[2]
+	return util.GetIPFromCIDR(cfg.Network.ServiceCIDRs()[0], 3)
 }
 
-func (cfg *ClusterSpec) ParseRolesList(hostList map[string]string, logger *log.Logger) ([]string, []string, []string, error) {
+// ValidateNetwork checks the CIDRs of pods and services, which are an IPv4 and an IPv6 CIDR in a dual-stack cluster,
+// and the IPv6 addresses of hosts.
+func (cfg *ClusterSpec) ValidateNetwork() error {
+	podsCIDRs, serviceCIDRs := cfg.Network.PodsCIDRs(), cfg.Network.ServiceCIDRs()
+	for _, cidrs := range [][]string{podsCIDRs, serviceCIDRs} {
+		if len(cidrs) == 0 || len(cidrs) > 2 {
+			return errors.New(fmt.Sprintf("Expected one CIDR or an IPv4 and an IPv6 CIDR, but got: %s", strings.Join(cidrs, ",")))
+		}
+		for _, cidr := range cidrs {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				return errors.New(fmt.Sprintf("Invalid CIDR: %s", cidr))
+			}
+		}
+		if len(cidrs) == 2 && (cidrOfFamily(cidrs, false) == "" || cidrOfFamily(cidrs, true) == "") {
+			return errors.New(fmt.Sprintf("Expected an IPv4 and an IPv6 CIDR, but got: %s", strings.Join(cidrs, ",")))
+		}
+	}
+	if len(podsCIDRs) != len(serviceCIDRs) {
+		return errors.New("The CIDRs of pods and services should be both single-stack or both dual-stack")
+	}
+
+	if cfg.Network.DualStack() {
+		switch cfg.Network.Plugin {
+		case "calico", "cilium", "", "none", "custom":
+		default:
+			return errors.New(fmt.Sprintf("The dual-stack cluster is not supported by the network plugin: %s", cfg.Network.Plugin))
+		}
+		if version, err := versionutil.ParseSemantic(cfg.Kubernetes.Version); err == nil && version.LessThan(versionutil.MustParseSemantic("v1.16.0")) {
+			return errors.New(fmt.Sprintf("The dual-stack cluster is not supported by kubernetes %s", cfg.Kubernetes.Version))
+		}
+	}
+
+	for _, host := range cfg.Hosts {
+		if host.InternalIPv6 == "" {
+			continue
+		}
+		if ip := net.ParseIP(host.InternalIPv6); ip == nil || ip.To4() != nil {
+			return errors.New(fmt.Sprintf("Invalid IPv6 address of %s: %s", host.Name, host.InternalIPv6))
+		}
+	}
+	return nil
+}
+
+// NodeIP returns the IP of the node given to kubelet, the IPv6 address is included in a dual-stack cluster.
+func (cfg *ClusterSpec) NodeIP(host *HostCfg) string {
+	if cfg.Network.DualStack() && host.InternalIPv6 != "" {
+		return fmt.Sprintf("%s,%s", host.InternalAddress, host.InternalIPv6)
+	}
+	return host.InternalAddress
+}
+
+// KubeletConfigOf returns the kubelet configuration of the given host, or the one of the whole cluster if host is nil.
+// The defaults, the cluster, the kubelet pools containing the host and the host itself are merged in order.
+func (cfg *ClusterSpec) KubeletConfigOf(host *HostCfg) KubeletConfig {
+	kubeletCfg := KubeletConfig{
+		KubeReserved:                     map[string]string{"cpu": DefaultReservedCPU, "memory": DefaultReservedMemory},
+		SystemReserved:                   map[string]string{"cpu": DefaultReservedCPU, "memory": DefaultReservedMemory},
+		EvictionHard:                     map[string]string{"memory.available": DefaultEvictionHardMemory},
+		EvictionSoft:                     map[string]string{"memory.available": DefaultEvictionSoftMemory},
+		EvictionSoftGracePeriod:          map[string]string{"memory.available": DefaultEvictionSoftGracePeriod},
+		EvictionMaxPodGracePeriod:        DefaultEvictionMaxPodGracePeriod,
+		EvictionPressureTransitionPeriod: DefaultEvictionPressureTransitionPeriod,
+		ContainerLogMaxSize:              DefaultContainerLogMaxSize,
+		ContainerLogMaxFiles:             DefaultContainerLogMaxFiles,
+	}
+	kubeletCfg.merge(&cfg.Kubernetes.Kubelet)
+	if host == nil {
+		return kubeletCfg
+	}
+	for i := range cfg.Kubernetes.KubeletPools {
+		if pool := &cfg.Kubernetes.KubeletPools[i]; pool.contains(host) {
+			kubeletCfg.merge(&pool.KubeletConfig)
+		}
+	}
+	if host.Kubelet != nil {
+		kubeletCfg.merge(host.Kubelet)
+	}
+	return kubeletCfg
+}
+
+// ValidateAudit checks the backend of the audit logging of kube-apiserver.
+// The files given are read when they are distributed to the masters.
+func (cfg *ClusterSpec) ValidateAudit() error {
+	audit := &cfg.Kubernetes.Audit
+	switch audit.Backend {
+	case "log":
+		if !filepath.IsAbs(audit.LogPath) {
+			return errors.New(fmt.Sprintf("Invalid audit log path: %s, it should be an absolute path", audit.LogPath))
+		}
+		if audit.LogMaxAge < 0 || audit.LogMaxBackup < 0 || audit.LogMaxSize < 0 {
+			return errors.New("The logMaxAge, logMaxBackup and logMaxSize of audit should not be negative")
+		}
+	case "webhook":
+		if audit.Enabled && audit.WebhookConfig == "" && audit.WebhookConfigFile == "" {
+			return errors.New("The webhookConfig or webhookConfigFile is required by the audit webhook backend")
+		}
+	default:
+		return errors.New(fmt.Sprintf("Invalid audit backend: %s, it should be log or webhook", audit.Backend))
+	}
+	return nil
+}
+
+// ValidateEncryption checks the provider of the encryption at rest, only the providers whose keys are generated by kk are accepted.
+func (cfg *ClusterSpec) ValidateEncryption() error {
+	switch cfg.Kubernetes.Encryption.Provider {
+	case "aescbc", "secretbox":
+		return nil
+	default:
+		return errors.New(fmt.Sprintf("Invalid encryption provider: %s, it should be aescbc or secretbox", cfg.Kubernetes.Encryption.Provider))
+	}
+}
+
+// ValidateAuthentication checks the OIDC and webhook configurations of kube-apiserver.
+// The files given are read when they are distributed to the masters.
+func (cfg *ClusterSpec) ValidateAuthentication() error {
+	authn := &cfg.Kubernetes.Authentication
+	if oidc := &authn.OIDC; oidc.Enabled() {
+		if u, err := url.Parse(oidc.IssuerURL); err != nil || u.Scheme != "https" || u.Hostname() == "" {
+			return errors.New(fmt.Sprintf("Invalid OIDC issuer URL: %s, only the https scheme is accepted", oidc.IssuerURL))
+		}
+		if oidc.ClientID == "" {
+			return errors.New("The clientID of OIDC is required if the issuerURL is given")
+		}
+	}
+
+	for _, v := range []struct{ name, value string }{
+		{"authentication webhook version", authn.Webhook.Version},
+		{"authorization webhook version", authn.AuthorizationWebhook.Version},
+	} {
+		if v.value != "" && v.value != "v1" && v.value != "v1beta1" {
+			return errors.New(fmt.Sprintf("Invalid %s: %s, it should be v1 or v1beta1", v.name, v.value))
+		}
+	}
+	for _, ttl := range []struct{ name, value string }{
+		{"cacheTTL of the authentication webhook", authn.Webhook.CacheTTL},
+		{"cacheAuthorizedTTL of the authorization webhook", authn.AuthorizationWebhook.CacheAuthorizedTTL},
+		{"cacheUnauthorizedTTL of the authorization webhook", authn.AuthorizationWebhook.CacheUnauthorizedTTL},
+	} {
+		if _, err := time.ParseDuration(ttl.value); ttl.value != "" && err != nil {
+			return errors.New(fmt.Sprintf("Invalid %s: %s", ttl.name, ttl.value))
+		}
+	}
+	return nil
+}
+
+// admissionPluginVersions are the versions of kubernetes supporting the admission plugins, an empty version means no limit.
+var admissionPluginVersions = map[string]struct{ since, until string }{
+	"PodSecurity":                   {since: "v1.23.0"},
+	"PodSecurityPolicy":             {until: "v1.25.0"},
+	"DefaultIngressClass":           {since: "v1.18.0"},
+	"CertificateApproval":           {since: "v1.18.0"},
+	"CertificateSigning":            {since: "v1.18.0"},
+	"CertificateSubjectRestriction": {since: "v1.18.0"},
+}
+
+// ValidateAdmission checks the admission plugins and their configurations against the version of kubernetes.
+// The configuration files given are read when they are distributed to the masters.
+func (cfg *ClusterSpec) ValidateAdmission() error {
+	admission := &cfg.Kubernetes.Admission
+	enabled := map[string]bool{}
+	for _, plugin := range admission.EnablePlugins {
+		enabled[plugin] = true
+	}
+	if admission.PodSecurityPolicy {
+		enabled["PodSecurityPolicy"] = true
+	}
+	if admission.PodSecurity.Enabled() {
+		enabled["PodSecurity"] = true
+	}
+
+	configured := map[string]bool{}
+	for _, pluginCfg := range admission.PluginConfigs {
+		if pluginCfg.Name == "" {
+			return errors.New("The name of the admission plugin config is required")
+		}
+		if configured[pluginCfg.Name] || (pluginCfg.Name == "PodSecurity" && admission.PodSecurity.Enabled()) {
+			return errors.New(fmt.Sprintf("The admission plugin %s is configured more than once", pluginCfg.Name))
+		}
+		if pluginCfg.Configuration == "" && pluginCfg.ConfigurationFile == "" {
+			return errors.New(fmt.Sprintf("The configuration or configurationFile of the admission plugin %s is required", pluginCfg.Name))
+		}
+		configured[pluginCfg.Name] = true
+		enabled[pluginCfg.Name] = true
+	}
+	for _, plugin := range admission.DisablePlugins {
+		if enabled[plugin] {
+			return errors.New(fmt.Sprintf("The admission plugin %s is both enabled and disabled", plugin))
+		}
+	}
+
+	if podSecurity := &admission.PodSecurity; podSecurity.Enabled() {
+		for _, level := range []string{podSecurity.Enforce, podSecurity.Audit, podSecurity.Warn} {
+			switch level {
+			case "privileged", "baseline", "restricted":
+			default:
+				return errors.New(fmt.Sprintf("Invalid PodSecurity level: %s, it should be privileged, baseline or restricted", level))
+			}
+		}
+	}
+
+	version, err := versionutil.ParseSemantic(cfg.Kubernetes.Version)
+	if err != nil {
+		return nil
+	}
+	for plugin := range enabled {
+		versions, ok := admissionPluginVersions[plugin]
+		if !ok {
+			continue
+		}
+		if (versions.since != "" && version.LessThan(versionutil.MustParseSemantic(versions.since))) ||
+			(versions.until != "" && !version.LessThan(versionutil.MustParseSemantic(versions.until))) {
+			return errors.New(fmt.Sprintf("The admission plugin %s is not supported by kubernetes %s", plugin, cfg.Kubernetes.Version))
+		}
+	}
+	return nil
+}
+
+// ExternalCloudProvider returns true if the cloud provider is integrated by an external cloud-controller-manager.
+func (cfg *ClusterSpec) ExternalCloudProvider() bool {
+	return cfg.Kubernetes.CloudProvider == CloudProviderExternal
+}
+
+// ValidateCloudProvider checks that the cloud-controller-manager is given for the external cloud provider.
+func (cfg *ClusterSpec) ValidateCloudProvider() error {
+	switch cfg.Kubernetes.CloudProvider {
+	case "":
+		return nil
+	case CloudProviderExternal:
+	default:
+		return errors.New(fmt.Sprintf("Unsupported cloud provider: %s, only the external cloud provider is supported", cfg.Kubernetes.CloudProvider))
+	}
+	ccm := &cfg.Kubernetes.CloudControllerManager
+	if ccm.Sources.Chart.Name == "" && len(ccm.Sources.Yaml.Path) == 0 {
+		return errors.New("The chart or the yaml files of the cloudControllerManager are required by the external cloud provider")
+	}
+	return nil
+}
+
+// ipvsSchedulers are the schedulers of ipvs accepted by kube-proxy.
+var ipvsSchedulers = []string{"rr", "wrr", "lc", "wlc", "lblc", "lblcr", "sh", "dh", "sed", "nq"}
+
+// ValidateKubeProxy checks the configuration of kube-proxy against the proxy mode.
+func (cfg *ClusterSpec) ValidateKubeProxy() error {
+	switch cfg.Kubernetes.ProxyMode {
+	case "ipvs", "iptables":
+	default:
+		return errors.New(fmt.Sprintf("Invalid proxy mode: %s, it should be ipvs or iptables", cfg.Kubernetes.ProxyMode))
+	}
+
+	kubeProxy := &cfg.Kubernetes.KubeProxy
+	ipvs := &kubeProxy.IPVS
+	if cfg.Kubernetes.ProxyMode != "ipvs" {
+		// the defaults of the ipvs options are set whatever the proxy mode is
+		if ipvs.Scheduler != DefaultIPVSScheduler || ipvs.StrictARP || len(ipvs.ExcludeCIDRs) > 0 ||
+			ipvs.TCPTimeout != "" || ipvs.TCPFinTimeout != "" || ipvs.UDPTimeout != "" {
+			return errors.New(fmt.Sprintf("The ipvs options of kube-proxy are not accepted by the proxy mode %s", cfg.Kubernetes.ProxyMode))
+		}
+	}
+	validScheduler := false
+	for _, scheduler := range ipvsSchedulers {
+		if ipvs.Scheduler == scheduler {
+			validScheduler = true
+		}
+	}
+	if !validScheduler {
+		return errors.New(fmt.Sprintf("Invalid ipvs scheduler: %s, it should be one of %s", ipvs.Scheduler, strings.Join(ipvsSchedulers, ",")))
+	}
+	for _, cidr := range ipvs.ExcludeCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.New(fmt.Sprintf("Invalid CIDR in the excludeCIDRs of ipvs: %s", cidr))
+		}
+	}
+
+	// the masqueradeBit 0 is taken as unset and replaced by the default
+	if bit := kubeProxy.IPTables.MasqueradeBit; bit < 1 || bit > 31 {
+		return errors.New(fmt.Sprintf("Invalid masqueradeBit of iptables: %d, it should be in the range [1, 31]", bit))
+	}
+	if kubeProxy.Conntrack.MaxPerCore < 0 || kubeProxy.Conntrack.Min < 0 {
+		return errors.New("The maxPerCore and min of conntrack should not be negative")
+	}
+	for _, period := range []struct{ name, value string }{
+		{"syncPeriod of ipvs", ipvs.SyncPeriod},
+		{"minSyncPeriod of ipvs", ipvs.MinSyncPeriod},
+		{"tcpTimeout of ipvs", ipvs.TCPTimeout},
+		{"tcpFinTimeout of ipvs", ipvs.TCPFinTimeout},
+		{"udpTimeout of ipvs", ipvs.UDPTimeout},
+		{"syncPeriod of iptables", kubeProxy.IPTables.SyncPeriod},
+		{"minSyncPeriod of iptables", kubeProxy.IPTables.MinSyncPeriod},
+		{"tcpEstablishedTimeout of conntrack", kubeProxy.Conntrack.TCPEstablishedTimeout},
+		{"tcpCloseWaitTimeout of conntrack", kubeProxy.Conntrack.TCPCloseWaitTimeout},
+	} {
+		if _, err := time.ParseDuration(period.value); period.value != "" && err != nil {
+			return errors.New(fmt.Sprintf("Invalid %s: %s", period.name, period.value))
+		}
+	}
+	return nil
+}
+
+// ServerTLSBootstrapEnabled returns true if any of the hosts in the k8s cluster requests its kubelet serving certificate from the cluster.
+func (cfg *ClusterSpec) ServerTLSBootstrapEnabled() bool {
+	for i := range cfg.Hosts {
+		if cfg.KubeletConfigOf(&cfg.Hosts[i]).ServerTLSBootstrap {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateKubelet checks the kubelet configuration against the version of kubernetes.
+func (cfg *ClusterSpec) ValidateKubelet() error {
+	if !cfg.ServerTLSBootstrapEnabled() {
+		return nil
+	}
+	// the CSR approver works with certificates.k8s.io/v1 which is available since v1.19
+	if version, err := versionutil.ParseSemantic(cfg.Kubernetes.Version); err == nil && version.LessThan(versionutil.MustParseSemantic("v1.19.0")) {
+		return errors.New(fmt.Sprintf("The serverTLSBootstrap of kubelet is not supported by kubernetes %s, v1.19+ is required", cfg.Kubernetes.Version))
+	}
+	return nil
+}
+
+// ValidateContainerManager checks the container runtime against the version of kubernetes.
+func (cfg *ClusterSpec) ValidateContainerManager() error {
+	if cfg.Kubernetes.ContainerManager != "" && cfg.Kubernetes.ContainerManager != "docker" {
+		return nil
+	}
+	// dockershim is removed from kubelet since v1.24
+	if version, err := versionutil.ParseSemantic(cfg.Kubernetes.Version); err == nil && !version.LessThan(versionutil.MustParseSemantic("v1.24.0")) {
+		return errors.New(fmt.Sprintf("The container manager docker is not supported by kubernetes %s, set the containerManager to containerd, crio or isula", cfg.Kubernetes.Version))
+	}
+	return nil
+}
+
+func (c *KubeletConfig) merge(src *KubeletConfig) {
+	for _, m := range []struct{ dst, src *map[string]string }{
+		{&c.KubeReserved, &src.KubeReserved},
+		{&c.SystemReserved, &src.SystemReserved},
+		{&c.EvictionHard, &src.EvictionHard},
+		{&c.EvictionSoft, &src.EvictionSoft},
+		{&c.EvictionSoftGracePeriod, &src.EvictionSoftGracePeriod},
+	} {
+		merged := map[string]string{}
+		for k, v := range *m.dst {
+			merged[k] = v
+		}
+		for k, v := range *m.src {
+			merged[k] = v
+		}
+		*m.dst = merged
+	}
+	if src.EvictionMaxPodGracePeriod != 0 {
+		c.EvictionMaxPodGracePeriod = src.EvictionMaxPodGracePeriod
+	}
+	if src.EvictionPressureTransitionPeriod != "" {
+		c.EvictionPressureTransitionPeriod = src.EvictionPressureTransitionPeriod
+	}
+	if src.ContainerLogMaxSize != "" {
+		c.ContainerLogMaxSize = src.ContainerLogMaxSize
+	}
+	if src.ContainerLogMaxFiles != 0 {
+		c.ContainerLogMaxFiles = src.ContainerLogMaxFiles
+	}
+	if src.ServerTLSBootstrap {
+		c.ServerTLSBootstrap = true
+	}
+}
+
+func (p *KubeletPool) contains(host *HostCfg) bool {
+	for _, name := range p.Hosts {
+		if name == host.Name {
+			return true
+		}
+	}
+	if len(p.Labels) == 0 {
+		return false
+	}
+	for k, v := range p.Labels {
+		if value, ok := host.Labels[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (cfg *ClusterSpec) ParseRolesList(hostList map[string]string, logger *log.Logger) ([]string, []string, []string, []string, error) {
 	etcdGroupList := []string{}
 	masterGroupList := []string{}
 	workerGroupList := []string{}
+	loadBalancerGroupList := []string{}
 
 	for _, host := range cfg.RoleGroups.Etcd {
 		if strings.Contains(host, "[") && strings.Contains(host, "]") && strings.Contains(host, ":") {
@@ -322,7 +804,18 @@ func (cfg *ClusterSpec) ParseRolesList(hostList map[string]string, logger *log.L
 			workerGroupList = append(workerGroupList, host)
 		}
 	}
-	return etcdGroupList, masterGroupList, workerGroupList, nil
+
+	for _, host := range cfg.RoleGroups.LoadBalancer {
+		if strings.Contains(host, "[") && strings.Contains(host, "]") && strings.Contains(host, ":") {
+			loadBalancerGroupList = append(loadBalancerGroupList, getHostsRange(host, hostList, "loadbalancer", logger)...)
+		} else {
+			if err := hostVerify(hostList, host, "loadbalancer"); err != nil {
+				logger.Fatal(err)
+			}
+			loadBalancerGroupList = append(loadBalancerGroupList, host)
+		}
+	}
+	return etcdGroupList, masterGroupList, workerGroupList, loadBalancerGroupList, nil
 }
 
 func getHostsRange(rangeStr string, hostList map[string]string, group string, logger *log.Logger) []string {
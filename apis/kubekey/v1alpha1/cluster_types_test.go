@@ -0,0 +1,56 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import "testing"
+
+func TestValidateNetwork(t *testing.T) {
+	tests := []struct {
+		name    string
+		network NetworkConfig
+		version string
+		ipv6    string
+		wantErr bool
+	}{
+		{name: "single-stack", network: NetworkConfig{Plugin: "calico", KubePodsCIDR: "10.233.64.0/18", KubeServiceCIDR: "10.233.0.0/18"}},
+		{name: "dual-stack", network: NetworkConfig{Plugin: "calico", KubePodsCIDR: "10.233.64.0/18,fd85:ee78:d8a6:8607::1:0000/112", KubeServiceCIDR: "10.233.0.0/18, fd00:10:96::/108"}, ipv6: "fd00::1"},
+		{name: "IPv6 first", network: NetworkConfig{Plugin: "cilium", KubePodsCIDR: "fd85:ee78:d8a6:8607::1:0000/112,10.233.64.0/18", KubeServiceCIDR: "fd00:10:96::/108,10.233.0.0/18"}},
+		{name: "no pods CIDR", network: NetworkConfig{Plugin: "calico", KubeServiceCIDR: "10.233.0.0/18"}, wantErr: true},
+		{name: "three CIDRs", network: NetworkConfig{Plugin: "calico", KubePodsCIDR: "10.233.64.0/18,fd00:1::/112,fd00:2::/112", KubeServiceCIDR: "10.233.0.0/18"}, wantErr: true},
+		{name: "invalid CIDR", network: NetworkConfig{Plugin: "calico", KubePodsCIDR: "10.233.64.0", KubeServiceCIDR: "10.233.0.0/18"}, wantErr: true},
+		{name: "two IPv4 CIDRs", network: NetworkConfig{Plugin: "calico", KubePodsCIDR: "10.233.64.0/18,10.234.64.0/18", KubeServiceCIDR: "10.233.0.0/18,fd00:10:96::/108"}, wantErr: true},
+		{name: "mixed stacks", network: NetworkConfig{Plugin: "calico", KubePodsCIDR: "10.233.64.0/18,fd00:1::/112", KubeServiceCIDR: "10.233.0.0/18"}, wantErr: true},
+		{name: "dual-stack unsupported by plugin", network: NetworkConfig{Plugin: "flannel", KubePodsCIDR: "10.233.64.0/18,fd00:1::/112", KubeServiceCIDR: "10.233.0.0/18,fd00:10:96::/108"}, wantErr: true},
+		{name: "dual-stack unsupported by kubernetes", network: NetworkConfig{Plugin: "calico", KubePodsCIDR: "10.233.64.0/18,fd00:1::/112", KubeServiceCIDR: "10.233.0.0/18,fd00:10:96::/108"}, version: "v1.15.12", wantErr: true},
+		{name: "invalid IPv6 address", network: NetworkConfig{Plugin: "calico", KubePodsCIDR: "10.233.64.0/18", KubeServiceCIDR: "10.233.0.0/18"}, ipv6: "172.16.0.1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := ClusterSpec{
+				Hosts:      []HostCfg{{Name: "node1", InternalIPv6: tt.ipv6}},
+				Network:    tt.network,
+				Kubernetes: Kubernetes{Version: "v1.19.8"},
+			}
+			if tt.version != "" {
+				cfg.Kubernetes.Version = tt.version
+			}
+			if err := cfg.ValidateNetwork(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateNetwork() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
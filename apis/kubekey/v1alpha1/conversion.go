@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"strings"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha2"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+	"sigs.k8s.io/yaml"
+)
+
+var _ conversion.Convertible = &Cluster{}
+
+// ConvertTo converts this Cluster to the Hub version (v1alpha2).
+func (src *Cluster) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*kubekeyapiv1alpha2.Cluster)
+	dst.ObjectMeta = src.ObjectMeta
+	if err := Convert_v1alpha1_ClusterSpec_To_v1alpha2_ClusterSpec(&src.Spec, &dst.Spec); err != nil {
+		return err
+	}
+	return convertByJSON(&src.Status, &dst.Status)
+}
+
+// ConvertFrom converts from the Hub version (v1alpha2) to this version.
+func (dst *Cluster) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*kubekeyapiv1alpha2.Cluster)
+	dst.ObjectMeta = src.ObjectMeta
+	if err := Convert_v1alpha2_ClusterSpec_To_v1alpha1_ClusterSpec(&src.Spec, &dst.Spec); err != nil {
+		return err
+	}
+	return convertByJSON(&src.Status, &dst.Status)
+}
+
+// Convert_v1alpha1_ClusterSpec_To_v1alpha2_ClusterSpec converts the spec of v1alpha1 to v1alpha2.
+// The fields shared by both versions are converted by their json representation, the KubeSphere configurations
+// kept as a yaml document in v1alpha1 are converted to a structured object.
+func Convert_v1alpha1_ClusterSpec_To_v1alpha2_ClusterSpec(in *ClusterSpec, out *kubekeyapiv1alpha2.ClusterSpec) error {
+	spec := in.DeepCopy()
+	configurations := strings.TrimSpace(spec.KubeSphere.Configurations)
+	spec.KubeSphere.Configurations = ""
+
+	*out = kubekeyapiv1alpha2.ClusterSpec{}
+	if err := convertByJSON(spec, out); err != nil {
+		return err
+	}
+
+	configurations = strings.TrimSpace(strings.TrimPrefix(configurations, "---"))
+	if configurations != "" {
+		raw, err := yaml.YAMLToJSON([]byte(configurations))
+		if err != nil {
+			return errors.Wrap(errors.WithStack(err), "Failed to convert the configurations of KubeSphere")
+		}
+		out.KubeSphere.Configurations = &runtime.RawExtension{Raw: raw}
+	}
+	return nil
+}
+
+// Convert_v1alpha2_ClusterSpec_To_v1alpha1_ClusterSpec converts the spec of v1alpha2 to v1alpha1.
+func Convert_v1alpha2_ClusterSpec_To_v1alpha1_ClusterSpec(in *kubekeyapiv1alpha2.ClusterSpec, out *ClusterSpec) error {
+	spec := in.DeepCopy()
+	configurations := spec.KubeSphere.Configurations
+	spec.KubeSphere.Configurations = nil
+
+	*out = ClusterSpec{}
+	if err := convertByJSON(spec, out); err != nil {
+		return err
+	}
+
+	if configurations != nil && len(configurations.Raw) != 0 {
+		content, err := yaml.JSONToYAML(configurations.Raw)
+		if err != nil {
+			return errors.Wrap(errors.WithStack(err), "Failed to convert the configurations of KubeSphere")
+		}
+		out.KubeSphere.Configurations = "---\n" + string(content)
+	}
+	return nil
+}
+
+// convertByJSON converts in to out through their json representation, fields are matched by their json names.
+// The names are matched case-insensitively, which also maps the "Conditions" of v1alpha1 status to the "conditions" of v1alpha2.
+func convertByJSON(in, out interface{}) error {
+	content, err := json.Marshal(in)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "Failed to marshal the object to be converted")
+	}
+	if err := json.Unmarshal(content, out); err != nil {
+		return errors.Wrap(errors.WithStack(err), "Failed to convert the object")
+	}
+	return nil
+}
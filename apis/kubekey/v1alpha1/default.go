@@ -21,120 +21,151 @@ import (
 	"os"
 	"strings"
 
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha2"
 	"github.com/kubesphere/kubekey/pkg/util"
 	log "github.com/sirupsen/logrus"
 )
 
 const (
-	DefaultPreDir              = "kubekey"
-	DefaultSSHPort             = 22
-	DefaultLBPort              = 6443
-	DefaultLBDomain            = "lb.kubesphere.local"
-	DefaultNetworkPlugin       = "calico"
-	DefaultPodsCIDR            = "10.233.64.0/18"
-	DefaultServiceCIDR         = "10.233.0.0/18"
-	DefaultKubeImageNamespace  = "kubesphere"
-	DefaultClusterName         = "cluster.local"
-	DefaultArch                = "amd64"
-	DefaultEtcdVersion         = "v3.4.13"
-	DefaultEtcdPort            = "2379"
-	DefaultKubeVersion         = "v1.17.9"
-	DefaultCalicoVersion       = "v3.16.3"
-	DefaultFlannelVersion      = "v0.12.0"
-	DefaultCniVersion          = "v0.8.6"
-	DefaultCiliumVersion       = "v1.8.3"
-	DefaultKubeovnVersion      = "v1.5.0"
-	DefaultHelmVersion         = "v3.2.1"
-	DefaultMaxPods             = 110
-	DefaultNodeCidrMaskSize    = 24
-	DefaultIPIPMode            = "Always"
-	DefaultVXLANMode           = "Never"
-	DefaultVethMTU             = 1440
-	DefaultBackendMode         = "vxlan"
-	DefaultProxyMode           = "ipvs"
-	DefaultCrioEndpoint        = "unix:///var/run/crio/crio.sock"
-	DefaultContainerdEndpoint  = "unix:///run/containerd/containerd.sock"
-	DefaultIsulaEndpoint       = "unix:///var/run/isulad.sock"
+	DefaultPreDir              = kubekeyapiv1alpha2.DefaultPreDir
+	DefaultSSHPort             = kubekeyapiv1alpha2.DefaultSSHPort
+	DefaultLBPort              = kubekeyapiv1alpha2.DefaultLBPort
+	DefaultLBDomain            = kubekeyapiv1alpha2.DefaultLBDomain
+	DefaultNetworkPlugin       = kubekeyapiv1alpha2.DefaultNetworkPlugin
+	DefaultPodsCIDR            = kubekeyapiv1alpha2.DefaultPodsCIDR
+	DefaultServiceCIDR         = kubekeyapiv1alpha2.DefaultServiceCIDR
+	DefaultKubeImageNamespace  = kubekeyapiv1alpha2.DefaultKubeImageNamespace
+	DefaultClusterName         = kubekeyapiv1alpha2.DefaultClusterName
+	DefaultArch                = kubekeyapiv1alpha2.DefaultArch
+	DefaultEtcdVersion         = kubekeyapiv1alpha2.DefaultEtcdVersion
+	DefaultEtcdPort            = kubekeyapiv1alpha2.DefaultEtcdPort
+	DefaultKubeVersion         = kubekeyapiv1alpha2.DefaultKubeVersion
+	DefaultCalicoVersion       = kubekeyapiv1alpha2.DefaultCalicoVersion
+	DefaultFlannelVersion      = kubekeyapiv1alpha2.DefaultFlannelVersion
+	DefaultCniVersion          = kubekeyapiv1alpha2.DefaultCniVersion
+	DefaultCiliumVersion       = kubekeyapiv1alpha2.DefaultCiliumVersion
+	DefaultKubeovnVersion      = kubekeyapiv1alpha2.DefaultKubeovnVersion
+	DefaultHelmVersion         = kubekeyapiv1alpha2.DefaultHelmVersion
+	DefaultMaxPods             = kubekeyapiv1alpha2.DefaultMaxPods
+	DefaultNodeCidrMaskSize    = kubekeyapiv1alpha2.DefaultNodeCidrMaskSize
+	DefaultIPIPMode            = kubekeyapiv1alpha2.DefaultIPIPMode
+	DefaultVXLANMode           = kubekeyapiv1alpha2.DefaultVXLANMode
+	DefaultVethMTU             = kubekeyapiv1alpha2.DefaultVethMTU
+	DefaultBackendMode         = kubekeyapiv1alpha2.DefaultBackendMode
+	DefaultProxyMode           = kubekeyapiv1alpha2.DefaultProxyMode
+	DefaultCrioEndpoint        = kubekeyapiv1alpha2.DefaultCrioEndpoint
+	DefaultContainerdEndpoint  = kubekeyapiv1alpha2.DefaultContainerdEndpoint
+	DefaultIsulaEndpoint       = kubekeyapiv1alpha2.DefaultIsulaEndpoint
 	Etcd                       = "etcd"
 	Master                     = "master"
 	Worker                     = "worker"
 	K8s                        = "k8s"
-	DefaultEtcdBackupDir       = "/var/backups/kube_etcd"
-	DefaultEtcdBackupPeriod    = 30
-	DefaultKeepBackNumber      = 5
-	DefaultEtcdBackupScriptDir = "/usr/local/bin/kube-scripts"
-	DefaultJoinCIDR            = "100.64.0.0/16"
-	DefaultNetworkType         = "geneve"
-	DefaultVlanID              = "100"
-	DefaultOvnLabel            = "node-role.kubernetes.io/master"
-	DefaultDPDKVersion         = "19.11"
-	DefaultDNSAddress          = "114.114.114.114"
+	DefaultEtcdBackupDir       = kubekeyapiv1alpha2.DefaultEtcdBackupDir
+	DefaultEtcdBackupPeriod    = kubekeyapiv1alpha2.DefaultEtcdBackupPeriod
+	DefaultKeepBackNumber      = kubekeyapiv1alpha2.DefaultKeepBackNumber
+	DefaultEtcdBackupScriptDir = kubekeyapiv1alpha2.DefaultEtcdBackupScriptDir
+	DefaultJoinCIDR            = kubekeyapiv1alpha2.DefaultJoinCIDR
+	DefaultNetworkType         = kubekeyapiv1alpha2.DefaultNetworkType
+	DefaultVlanID              = kubekeyapiv1alpha2.DefaultVlanID
+	DefaultOvnLabel            = kubekeyapiv1alpha2.DefaultOvnLabel
+	DefaultDPDKVersion         = kubekeyapiv1alpha2.DefaultDPDKVersion
+	DefaultDNSAddress          = kubekeyapiv1alpha2.DefaultDNSAddress
+
+	DefaultReservedCPU                      = kubekeyapiv1alpha2.DefaultReservedCPU
+	DefaultReservedMemory                   = kubekeyapiv1alpha2.DefaultReservedMemory
+	DefaultEvictionHardMemory               = kubekeyapiv1alpha2.DefaultEvictionHardMemory
+	DefaultEvictionSoftMemory               = kubekeyapiv1alpha2.DefaultEvictionSoftMemory
+	DefaultEvictionSoftGracePeriod          = kubekeyapiv1alpha2.DefaultEvictionSoftGracePeriod
+	DefaultEvictionMaxPodGracePeriod        = kubekeyapiv1alpha2.DefaultEvictionMaxPodGracePeriod
+	DefaultEvictionPressureTransitionPeriod = kubekeyapiv1alpha2.DefaultEvictionPressureTransitionPeriod
+	DefaultContainerLogMaxSize              = kubekeyapiv1alpha2.DefaultContainerLogMaxSize
+	DefaultContainerLogMaxFiles             = kubekeyapiv1alpha2.DefaultContainerLogMaxFiles
+
+	DefaultAuditBackend      = kubekeyapiv1alpha2.DefaultAuditBackend
+	DefaultAuditLogPath      = kubekeyapiv1alpha2.DefaultAuditLogPath
+	DefaultAuditLogMaxAge    = kubekeyapiv1alpha2.DefaultAuditLogMaxAge
+	DefaultAuditLogMaxBackup = kubekeyapiv1alpha2.DefaultAuditLogMaxBackup
+	DefaultAuditLogMaxSize   = kubekeyapiv1alpha2.DefaultAuditLogMaxSize
+
+	DefaultEncryptionProvider = kubekeyapiv1alpha2.DefaultEncryptionProvider
+
+	DefaultPodSecurityLevel   = kubekeyapiv1alpha2.DefaultPodSecurityLevel
+	DefaultPodSecurityVersion = kubekeyapiv1alpha2.DefaultPodSecurityVersion
+
+	DefaultIPVSScheduler = kubekeyapiv1alpha2.DefaultIPVSScheduler
+
+	CloudProviderExternal = kubekeyapiv1alpha2.CloudProviderExternal
+
+	DefaultNodeCidrMaskSizeIPv6 = kubekeyapiv1alpha2.DefaultNodeCidrMaskSizeIPv6
+
+	EtcdTypeKubeKey  = kubekeyapiv1alpha2.EtcdTypeKubeKey
+	EtcdTypeKubeadm  = kubekeyapiv1alpha2.EtcdTypeKubeadm
+	EtcdTypeExternal = kubekeyapiv1alpha2.EtcdTypeExternal
+
+	InternalLoadbalancerHaproxy = kubekeyapiv1alpha2.InternalLoadbalancerHaproxy
+	InternalLoadbalancerKubeVip = kubekeyapiv1alpha2.InternalLoadbalancerKubeVip
 )
 
+// SetDefaultClusterSpec returns a copy of the cluster spec with default values filled in, and groups the hosts by role.
+// The defaults are set by the shared defaulting of v1alpha2, the ones which are only meaningful to kk are set here.
 func (cfg *ClusterSpec) SetDefaultClusterSpec(incluster bool, logger *log.Logger) (*ClusterSpec, *HostGroups, error) {
-	clusterCfg := ClusterSpec{}
+	spec := kubekeyapiv1alpha2.ClusterSpec{}
+	if err := Convert_v1alpha1_ClusterSpec_To_v1alpha2_ClusterSpec(cfg, &spec); err != nil {
+		return nil, nil, err
+	}
+	kubekeyapiv1alpha2.SetDefaultClusterSpec(&spec)
 
-	clusterCfg.Hosts = SetDefaultHostsCfg(cfg)
-	clusterCfg.RoleGroups = cfg.RoleGroups
-	hostGroups, err := clusterCfg.GroupHosts(logger)
-	if err != nil {
+	clusterCfg := ClusterSpec{}
+	if err := Convert_v1alpha2_ClusterSpec_To_v1alpha1_ClusterSpec(&spec, &clusterCfg); err != nil {
 		return nil, nil, err
 	}
-	clusterCfg.ControlPlaneEndpoint = SetDefaultLBCfg(cfg, hostGroups.Master, incluster)
-	clusterCfg.Network = SetDefaultNetworkCfg(cfg)
-	clusterCfg.Kubernetes = SetDefaultClusterCfg(cfg)
-	clusterCfg.Registry = cfg.Registry
-	clusterCfg.Addons = cfg.Addons
+	// keep the configurations of KubeSphere as they are written by users
 	clusterCfg.KubeSphere = cfg.KubeSphere
 
-	if cfg.Kubernetes.ClusterName == "" {
-		clusterCfg.Kubernetes.ClusterName = DefaultClusterName
+	if err := clusterCfg.ValidateNetwork(); err != nil {
+		return nil, nil, err
+	}
+	if err := clusterCfg.ValidateKubelet(); err != nil {
+		return nil, nil, err
+	}
+	if err := clusterCfg.ValidateContainerManager(); err != nil {
+		return nil, nil, err
+	}
+	if err := clusterCfg.ValidateAudit(); err != nil {
+		return nil, nil, err
+	}
+	if err := clusterCfg.ValidateEncryption(); err != nil {
+		return nil, nil, err
+	}
+	if err := clusterCfg.ValidateAuthentication(); err != nil {
+		return nil, nil, err
 	}
-	if cfg.Kubernetes.Version == "" {
-		clusterCfg.Kubernetes.Version = DefaultKubeVersion
+	if err := clusterCfg.ValidateAdmission(); err != nil {
+		return nil, nil, err
 	}
-	if cfg.Kubernetes.MaxPods == 0 {
-		clusterCfg.Kubernetes.MaxPods = DefaultMaxPods
+	if err := clusterCfg.ValidateCloudProvider(); err != nil {
+		return nil, nil, err
 	}
-	if cfg.Kubernetes.NodeCidrMaskSize == 0 {
-		clusterCfg.Kubernetes.NodeCidrMaskSize = DefaultNodeCidrMaskSize
+	if err := clusterCfg.ValidateKubeProxy(); err != nil {
+		return nil, nil, err
 	}
-	if cfg.Kubernetes.ProxyMode == "" {
-		clusterCfg.Kubernetes.ProxyMode = DefaultProxyMode
+
+	clusterCfg.Hosts = SetDefaultHostsCfg(&clusterCfg)
+	hostGroups, err := clusterCfg.GroupHosts(logger)
+	if err != nil {
+		return nil, nil, err
 	}
+	clusterCfg.ControlPlaneEndpoint = SetDefaultLBCfg(&clusterCfg, hostGroups.Master, incluster)
 	return &clusterCfg, hostGroups, nil
 }
 
+// SetDefaultHostsCfg expands the private key path of hosts which is relative to the home directory of current user.
 func SetDefaultHostsCfg(cfg *ClusterSpec) []HostCfg {
 	var hostscfg []HostCfg
-	if len(cfg.Hosts) == 0 {
-		return nil
-	}
 	for _, host := range cfg.Hosts {
-		if len(host.Address) == 0 && len(host.InternalAddress) > 0 {
-			host.Address = host.InternalAddress
-		}
-		if len(host.InternalAddress) == 0 && len(host.Address) > 0 {
-			host.InternalAddress = host.Address
-		}
-		if host.User == "" {
-			host.User = "root"
-		}
-		if host.Port == 0 {
-			host.Port = DefaultSSHPort
-		}
-		if host.PrivateKey == "" {
-			if host.Password == "" && host.PrivateKeyPath == "" {
-				host.PrivateKeyPath = "~/.ssh/id_rsa"
-			}
-			if host.PrivateKeyPath != "" && strings.HasPrefix(strings.TrimSpace(host.PrivateKeyPath), "~/") {
-				homeDir, _ := util.Home()
-				host.PrivateKeyPath = strings.Replace(host.PrivateKeyPath, "~/", fmt.Sprintf("%s/", homeDir), 1)
-			}
-		}
-
-		if host.Arch == "" {
-			host.Arch = DefaultArch
+		if host.PrivateKey == "" && strings.HasPrefix(strings.TrimSpace(host.PrivateKeyPath), "~/") {
+			homeDir, _ := util.Home()
+			host.PrivateKeyPath = strings.Replace(host.PrivateKeyPath, "~/", fmt.Sprintf("%s/", homeDir), 1)
 		}
 		hostscfg = append(hostscfg, host)
 	}
@@ -143,15 +174,40 @@ func SetDefaultHostsCfg(cfg *ClusterSpec) []HostCfg {
 
 func SetDefaultLBCfg(cfg *ClusterSpec, masterGroup []HostCfg, incluster bool) ControlPlaneEndpoint {
 	if !incluster {
-		//The detection is not an HA environment, and the address at LB does not need input
-		if len(masterGroup) == 1 && cfg.ControlPlaneEndpoint.Address != "" {
-			fmt.Println("When the environment is not HA, the LB address does not need to be entered, so delete the corresponding value.")
-			os.Exit(0)
+		//The hosts of the loadbalancer role group hold the LB address as a VIP by keepalived
+		if len(cfg.RoleGroups.LoadBalancer) > 0 {
+			if cfg.ControlPlaneEndpoint.InternalLoadbalancer != "" {
+				fmt.Println("The internal load balancer can not be used with the loadbalancer role group, so delete one of them.")
+				os.Exit(0)
+			}
+			if cfg.ControlPlaneEndpoint.Address == "" {
+				fmt.Println("When the loadbalancer role group is set, You must set the value of the LB address, which is used as the VIP.")
+				os.Exit(0)
+			}
 		}
 
-		//Check whether LB should be configured
-		if len(masterGroup) >= 3 && cfg.ControlPlaneEndpoint.Address == "" {
-			fmt.Println("When the environment has at least three masters, You must set the value of the LB address.")
+		switch cfg.ControlPlaneEndpoint.InternalLoadbalancer {
+		case "":
+			//The detection is not an HA environment, and the address at LB does not need input
+			if len(masterGroup) == 1 && cfg.ControlPlaneEndpoint.Address != "" && len(cfg.RoleGroups.LoadBalancer) == 0 {
+				fmt.Println("When the environment is not HA, the LB address does not need to be entered, so delete the corresponding value.")
+				os.Exit(0)
+			}
+
+			//Check whether LB should be configured
+			if len(masterGroup) >= 3 && cfg.ControlPlaneEndpoint.Address == "" {
+				fmt.Println("When the environment has at least three masters, You must set the value of the LB address or enable the internal load balancer.")
+				os.Exit(0)
+			}
+		case InternalLoadbalancerHaproxy:
+		case InternalLoadbalancerKubeVip:
+			//The address is held by kube-vip as a VIP
+			if cfg.ControlPlaneEndpoint.Address == "" {
+				fmt.Println("When the internal load balancer is kube-vip, You must set the value of the LB address, which is used as the VIP.")
+				os.Exit(0)
+			}
+		default:
+			fmt.Printf("The internal load balancer %s is not supported, it should be haproxy or kube-vip.\n", cfg.ControlPlaneEndpoint.InternalLoadbalancer)
 			os.Exit(0)
 		}
 	}
@@ -159,82 +215,6 @@ func SetDefaultLBCfg(cfg *ClusterSpec, masterGroup []HostCfg, incluster bool) Co
 	if cfg.ControlPlaneEndpoint.Address == "" {
 		cfg.ControlPlaneEndpoint.Address = masterGroup[0].InternalAddress
 	}
-	if cfg.ControlPlaneEndpoint.Domain == "" {
-		cfg.ControlPlaneEndpoint.Domain = DefaultLBDomain
-	}
-	if cfg.ControlPlaneEndpoint.Port == 0 {
-		cfg.ControlPlaneEndpoint.Port = DefaultLBPort
-	}
 	defaultLbCfg := cfg.ControlPlaneEndpoint
 	return defaultLbCfg
 }
-
-func SetDefaultNetworkCfg(cfg *ClusterSpec) NetworkConfig {
-	if cfg.Network.Plugin == "" {
-		cfg.Network.Plugin = DefaultNetworkPlugin
-	}
-	if cfg.Network.KubePodsCIDR == "" {
-		cfg.Network.KubePodsCIDR = DefaultPodsCIDR
-	}
-	if cfg.Network.KubeServiceCIDR == "" {
-		cfg.Network.KubeServiceCIDR = DefaultServiceCIDR
-	}
-	if cfg.Network.Calico.IPIPMode == "" {
-		cfg.Network.Calico.IPIPMode = DefaultIPIPMode
-	}
-	if cfg.Network.Calico.VXLANMode == "" {
-		cfg.Network.Calico.VXLANMode = DefaultVXLANMode
-	}
-	if cfg.Network.Calico.VethMTU == 0 {
-		cfg.Network.Calico.VethMTU = DefaultVethMTU
-	}
-	if cfg.Network.Flannel.BackendMode == "" {
-		cfg.Network.Flannel.BackendMode = DefaultBackendMode
-	}
-	// kube-ovn default config
-	if cfg.Network.Kubeovn.JoinCIDR == "" {
-		cfg.Network.Kubeovn.JoinCIDR = DefaultJoinCIDR
-	}
-	if cfg.Network.Kubeovn.Label == "" {
-		cfg.Network.Kubeovn.Label = DefaultOvnLabel
-	}
-	if cfg.Network.Kubeovn.VlanID == "" {
-		cfg.Network.Kubeovn.VlanID = DefaultVlanID
-	}
-	if cfg.Network.Kubeovn.NetworkType == "" {
-		cfg.Network.Kubeovn.NetworkType = DefaultNetworkType
-	}
-	if cfg.Network.Kubeovn.PingerExternalAddress == "" {
-		cfg.Network.Kubeovn.PingerExternalAddress = DefaultDNSAddress
-	}
-	if cfg.Network.Kubeovn.DpdkVersion == "" {
-		cfg.Network.Kubeovn.DpdkVersion = DefaultDPDKVersion
-	}
-	defaultNetworkCfg := cfg.Network
-
-	return defaultNetworkCfg
-}
-
-func SetDefaultClusterCfg(cfg *ClusterSpec) Kubernetes {
-	if cfg.Kubernetes.Version == "" {
-		cfg.Kubernetes.Version = DefaultKubeVersion
-	}
-	if cfg.Kubernetes.ClusterName == "" {
-		cfg.Kubernetes.ClusterName = DefaultClusterName
-	}
-	if cfg.Kubernetes.EtcdBackupDir == "" {
-		cfg.Kubernetes.EtcdBackupDir = DefaultEtcdBackupDir
-	}
-	if cfg.Kubernetes.EtcdBackupPeriod == 0 {
-		cfg.Kubernetes.EtcdBackupPeriod = DefaultEtcdBackupPeriod
-	}
-	if cfg.Kubernetes.KeepBackupNumber == 0 {
-		cfg.Kubernetes.KeepBackupNumber = DefaultKeepBackNumber
-	}
-	if cfg.Kubernetes.EtcdBackupScriptDir == "" {
-		cfg.Kubernetes.EtcdBackupScriptDir = DefaultEtcdBackupScriptDir
-	}
-	defaultClusterCfg := cfg.Kubernetes
-
-	return defaultClusterCfg
-}
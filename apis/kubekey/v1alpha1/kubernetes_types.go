@@ -22,6 +22,7 @@ type Kubernetes struct {
 	MasqueradeAll            bool     `yaml:"masqueradeAll" json:"masqueradeAll,omitempty"`
 	MaxPods                  int      `yaml:"maxPods" json:"maxPods,omitempty"`
 	NodeCidrMaskSize         int      `yaml:"nodeCidrMaskSize" json:"nodeCidrMaskSize,omitempty"`
+	NodeCidrMaskSizeIPv6     int      `yaml:"nodeCidrMaskSizeIPv6" json:"nodeCidrMaskSizeIPv6,omitempty"`
 	ApiserverCertExtraSans   []string `yaml:"apiserverCertExtraSans" json:"apiserverCertExtraSans,omitempty"`
 	ProxyMode                string   `yaml:"proxyMode" json:"proxyMode,omitempty"`
 	EtcdBackupDir            string   `yaml:"etcdBackupDir" json:"etcdBackupDir,omitempty"`
@@ -30,4 +31,234 @@ type Kubernetes struct {
 	EtcdBackupScriptDir      string   `yaml:"etcdBackupScript" json:"etcdBackupScript,omitempty"`
 	ContainerManager         string   `yaml:"containerManager" json:"containerManager,omitempty"`
 	ContainerRuntimeEndpoint string   `yaml:"containerRuntimeEndpoint" json:"containerRuntimeEndpoint,omitempty"`
+	// ApiServerArgs, ControllerManagerArgs, SchedulerArgs and KubeProxyArgs are merged over the default flags of the components.
+	ApiServerArgs                 map[string]string `yaml:"apiserverArgs" json:"apiserverArgs,omitempty"`
+	ControllerManagerArgs         map[string]string `yaml:"controllerManagerArgs" json:"controllerManagerArgs,omitempty"`
+	SchedulerArgs                 map[string]string `yaml:"schedulerArgs" json:"schedulerArgs,omitempty"`
+	KubeProxyArgs                 map[string]string `yaml:"kubeProxyArgs" json:"kubeProxyArgs,omitempty"`
+	ApiServerExtraVolumes         []HostPathMount   `yaml:"apiserverExtraVolumes" json:"apiserverExtraVolumes,omitempty"`
+	ControllerManagerExtraVolumes []HostPathMount   `yaml:"controllerManagerExtraVolumes" json:"controllerManagerExtraVolumes,omitempty"`
+	SchedulerExtraVolumes         []HostPathMount   `yaml:"schedulerExtraVolumes" json:"schedulerExtraVolumes,omitempty"`
+	// FeatureGates are merged over the default feature gates of all the components.
+	FeatureGates map[string]bool `yaml:"featureGates" json:"featureGates,omitempty"`
+	// KubeadmConfigPatches are applied to the kubeadm configuration generated by kk in order.
+	KubeadmConfigPatches []KubeadmConfigPatch `yaml:"kubeadmConfigPatches" json:"kubeadmConfigPatches,omitempty"`
+	// Kubelet is the kubelet configuration of all the nodes, it can be overridden by KubeletPools and by the hosts.
+	Kubelet      KubeletConfig `yaml:"kubelet" json:"kubelet,omitempty"`
+	KubeletPools []KubeletPool `yaml:"kubeletPools" json:"kubeletPools,omitempty"`
+	Audit        Audit         `yaml:"audit" json:"audit,omitempty"`
+	Encryption   Encryption    `yaml:"encryption" json:"encryption,omitempty"`
+	// Authentication is the OIDC and webhook authentication and the webhook authorization of kube-apiserver.
+	Authentication Authentication `yaml:"authentication" json:"authentication,omitempty"`
+	Admission      Admission      `yaml:"admission" json:"admission,omitempty"`
+	// CloudProvider is empty or external. With external, the cloud provider is integrated by CloudControllerManager, which is installed before the network plugin.
+	CloudProvider          string `yaml:"cloudProvider" json:"cloudProvider,omitempty"`
+	CloudControllerManager Addon  `yaml:"cloudControllerManager" json:"cloudControllerManager,omitempty"`
+	// KubeProxy is the ipvs, iptables and conntrack configuration of kube-proxy, the mode and masqueradeAll are given by ProxyMode and MasqueradeAll.
+	KubeProxy KubeProxy `yaml:"kubeProxy" json:"kubeProxy,omitempty"`
+}
+
+// KubeProxy defines the KubeProxyConfiguration of kube-proxy.
+// It is rendered into the kubeadm configuration, and patched into the kube-proxy ConfigMap of existing clusters.
+type KubeProxy struct {
+	IPVS      KubeProxyIPVS      `yaml:"ipvs" json:"ipvs,omitempty"`
+	IPTables  KubeProxyIPTables  `yaml:"iptables" json:"iptables,omitempty"`
+	Conntrack KubeProxyConntrack `yaml:"conntrack" json:"conntrack,omitempty"`
+}
+
+// KubeProxyIPVS defines the options of the ipvs proxy mode, they are only accepted if the ProxyMode is ipvs.
+type KubeProxyIPVS struct {
+	Scheduler string `yaml:"scheduler" json:"scheduler,omitempty"`
+	// StrictARP stops the nodes answering ARP requests for the addresses of kube-ipvs0, which is required by MetalLB.
+	StrictARP     bool     `yaml:"strictARP" json:"strictARP,omitempty"`
+	ExcludeCIDRs  []string `yaml:"excludeCIDRs" json:"excludeCIDRs,omitempty"`
+	SyncPeriod    string   `yaml:"syncPeriod" json:"syncPeriod,omitempty"`
+	MinSyncPeriod string   `yaml:"minSyncPeriod" json:"minSyncPeriod,omitempty"`
+	TCPTimeout    string   `yaml:"tcpTimeout" json:"tcpTimeout,omitempty"`
+	TCPFinTimeout string   `yaml:"tcpFinTimeout" json:"tcpFinTimeout,omitempty"`
+	UDPTimeout    string   `yaml:"udpTimeout" json:"udpTimeout,omitempty"`
+}
+
+// KubeProxyIPTables defines the options of the iptables rules, which are used in both of the proxy modes.
+type KubeProxyIPTables struct {
+	MasqueradeBit int    `yaml:"masqueradeBit" json:"masqueradeBit,omitempty"`
+	SyncPeriod    string `yaml:"syncPeriod" json:"syncPeriod,omitempty"`
+	MinSyncPeriod string `yaml:"minSyncPeriod" json:"minSyncPeriod,omitempty"`
+}
+
+// KubeProxyConntrack defines the conntrack limits and timeouts set by kube-proxy on the nodes.
+type KubeProxyConntrack struct {
+	MaxPerCore            int    `yaml:"maxPerCore" json:"maxPerCore,omitempty"`
+	Min                   int    `yaml:"min" json:"min,omitempty"`
+	TCPEstablishedTimeout string `yaml:"tcpEstablishedTimeout" json:"tcpEstablishedTimeout,omitempty"`
+	TCPCloseWaitTimeout   string `yaml:"tcpCloseWaitTimeout" json:"tcpCloseWaitTimeout,omitempty"`
+}
+
+// Admission defines the admission plugins of kube-apiserver and their configurations.
+// The AdmissionConfiguration and the plugin configs are distributed to /etc/kubernetes/admission on every master.
+type Admission struct {
+	EnablePlugins  []string `yaml:"enablePlugins" json:"enablePlugins,omitempty"`
+	DisablePlugins []string `yaml:"disablePlugins" json:"disablePlugins,omitempty"`
+	// PluginConfigs are referred by the AdmissionConfiguration, the plugins configured are enabled as well.
+	PluginConfigs []AdmissionPluginConfig `yaml:"pluginConfigs" json:"pluginConfigs,omitempty"`
+	PodSecurity   PodSecurity             `yaml:"podSecurity" json:"podSecurity,omitempty"`
+	// PodSecurityPolicy enables the PodSecurityPolicy plugin, and installs a privileged policy for the nodes and kube-system and a baseline policy for the others.
+	PodSecurityPolicy bool `yaml:"podSecurityPolicy" json:"podSecurityPolicy,omitempty"`
+}
+
+// AdmissionPluginConfig defines the configuration of an admission plugin.
+type AdmissionPluginConfig struct {
+	Name string `yaml:"name" json:"name,omitempty"`
+	// Configuration is the config of the plugin in YAML, ConfigurationFile is the path of the file on the machine running kk.
+	Configuration     string `yaml:"configuration" json:"configuration,omitempty"`
+	ConfigurationFile string `yaml:"configurationFile" json:"configurationFile,omitempty"`
+}
+
+// PodSecurity defines the cluster-wide defaults and exemptions of the PodSecurity admission plugin, it is configured if any of the levels is given.
+type PodSecurity struct {
+	Enforce              string   `yaml:"enforce" json:"enforce,omitempty"`
+	EnforceVersion       string   `yaml:"enforceVersion" json:"enforceVersion,omitempty"`
+	Audit                string   `yaml:"audit" json:"audit,omitempty"`
+	AuditVersion         string   `yaml:"auditVersion" json:"auditVersion,omitempty"`
+	Warn                 string   `yaml:"warn" json:"warn,omitempty"`
+	WarnVersion          string   `yaml:"warnVersion" json:"warnVersion,omitempty"`
+	ExemptNamespaces     []string `yaml:"exemptNamespaces" json:"exemptNamespaces,omitempty"`
+	ExemptUsernames      []string `yaml:"exemptUsernames" json:"exemptUsernames,omitempty"`
+	ExemptRuntimeClasses []string `yaml:"exemptRuntimeClasses" json:"exemptRuntimeClasses,omitempty"`
+}
+
+// Enabled returns true if any of the levels of PodSecurity is given.
+func (p *PodSecurity) Enabled() bool {
+	return p.Enforce != "" || p.Audit != "" || p.Warn != ""
+}
+
+// Configured returns true if the AdmissionConfiguration is required by kube-apiserver.
+func (a *Admission) Configured() bool {
+	return len(a.PluginConfigs) > 0 || a.PodSecurity.Enabled()
+}
+
+// Authentication defines the OIDC and webhook authentication and the webhook authorization of kube-apiserver.
+// The CA and the kubeconfig files are distributed to /etc/kubernetes/authn on every master.
+type Authentication struct {
+	OIDC                 OIDC                 `yaml:"oidc" json:"oidc,omitempty"`
+	Webhook              TokenWebhook         `yaml:"webhook" json:"webhook,omitempty"`
+	AuthorizationWebhook AuthorizationWebhook `yaml:"authorizationWebhook" json:"authorizationWebhook,omitempty"`
+}
+
+// OIDC defines the OpenID Connect token authenticator, it is enabled if IssuerURL is given.
+type OIDC struct {
+	IssuerURL      string            `yaml:"issuerURL" json:"issuerURL,omitempty"`
+	ClientID       string            `yaml:"clientID" json:"clientID,omitempty"`
+	UsernameClaim  string            `yaml:"usernameClaim" json:"usernameClaim,omitempty"`
+	UsernamePrefix string            `yaml:"usernamePrefix" json:"usernamePrefix,omitempty"`
+	GroupsClaim    string            `yaml:"groupsClaim" json:"groupsClaim,omitempty"`
+	GroupsPrefix   string            `yaml:"groupsPrefix" json:"groupsPrefix,omitempty"`
+	RequiredClaims map[string]string `yaml:"requiredClaims" json:"requiredClaims,omitempty"`
+	SigningAlgs    []string          `yaml:"signingAlgs" json:"signingAlgs,omitempty"`
+	// CA is the PEM encoded CA of the issuer, CAFile is the path of the file on the machine running kk. The host's root CAs are used if neither is given.
+	CA     string `yaml:"ca" json:"ca,omitempty"`
+	CAFile string `yaml:"caFile" json:"caFile,omitempty"`
+}
+
+// Enabled returns true if the OIDC authenticator is configured.
+func (o *OIDC) Enabled() bool {
+	return o.IssuerURL != ""
+}
+
+// TokenWebhook defines the webhook token authenticator, it is enabled if Config or ConfigFile is given.
+type TokenWebhook struct {
+	// Config is the kubeconfig of the webhook in YAML, ConfigFile is the path of the file on the machine running kk.
+	Config     string `yaml:"config" json:"config,omitempty"`
+	ConfigFile string `yaml:"configFile" json:"configFile,omitempty"`
+	CacheTTL   string `yaml:"cacheTTL" json:"cacheTTL,omitempty"`
+	Version    string `yaml:"version" json:"version,omitempty"`
+}
+
+// Enabled returns true if the webhook token authenticator is configured.
+func (w *TokenWebhook) Enabled() bool {
+	return w.Config != "" || w.ConfigFile != ""
+}
+
+// AuthorizationWebhook defines the webhook authorizer which is consulted after Node and RBAC, it is enabled if Config or ConfigFile is given.
+type AuthorizationWebhook struct {
+	// Config is the kubeconfig of the webhook in YAML, ConfigFile is the path of the file on the machine running kk.
+	Config               string `yaml:"config" json:"config,omitempty"`
+	ConfigFile           string `yaml:"configFile" json:"configFile,omitempty"`
+	CacheAuthorizedTTL   string `yaml:"cacheAuthorizedTTL" json:"cacheAuthorizedTTL,omitempty"`
+	CacheUnauthorizedTTL string `yaml:"cacheUnauthorizedTTL" json:"cacheUnauthorizedTTL,omitempty"`
+	Version              string `yaml:"version" json:"version,omitempty"`
+}
+
+// Enabled returns true if the webhook authorizer is configured.
+func (w *AuthorizationWebhook) Enabled() bool {
+	return w.Config != "" || w.ConfigFile != ""
+}
+
+// Enabled returns true if any of the authenticators or the authorizer is configured.
+func (a *Authentication) Enabled() bool {
+	return a.OIDC.Enabled() || a.Webhook.Enabled() || a.AuthorizationWebhook.Enabled()
+}
+
+// Encryption defines the encryption at rest of the resources stored in etcd.
+type Encryption struct {
+	Enabled   bool     `yaml:"enabled" json:"enabled,omitempty"`
+	Provider  string   `yaml:"provider" json:"provider,omitempty"`
+	Resources []string `yaml:"resources" json:"resources,omitempty"`
+}
+
+// Audit defines the audit policy and backend of kube-apiserver.
+type Audit struct {
+	Enabled bool `yaml:"enabled" json:"enabled,omitempty"`
+	// Policy is the audit policy in YAML, PolicyFile is the path of the policy file on the machine running kk. A default policy is used if neither is given.
+	Policy     string `yaml:"policy" json:"policy,omitempty"`
+	PolicyFile string `yaml:"policyFile" json:"policyFile,omitempty"`
+	Backend    string `yaml:"backend" json:"backend,omitempty"`
+	LogPath    string `yaml:"logPath" json:"logPath,omitempty"`
+	LogMaxAge  int    `yaml:"logMaxAge" json:"logMaxAge,omitempty"`
+	// LogMaxBackup is the maximum number of rotated log files to keep.
+	LogMaxBackup int `yaml:"logMaxBackup" json:"logMaxBackup,omitempty"`
+	// LogMaxSize is the maximum size in megabytes of the log file before it is rotated.
+	LogMaxSize int `yaml:"logMaxSize" json:"logMaxSize,omitempty"`
+	// WebhookConfig is the kubeconfig of the webhook backend in YAML, WebhookConfigFile is the path of the file on the machine running kk.
+	WebhookConfig     string `yaml:"webhookConfig" json:"webhookConfig,omitempty"`
+	WebhookConfigFile string `yaml:"webhookConfigFile" json:"webhookConfigFile,omitempty"`
+}
+
+// KubeletConfig defines the resource reservations and eviction thresholds of kubelet.
+// The maps are merged key by key over the less specific configurations, the other fields take effect if they are set.
+type KubeletConfig struct {
+	KubeReserved                     map[string]string `yaml:"kubeReserved" json:"kubeReserved,omitempty"`
+	SystemReserved                   map[string]string `yaml:"systemReserved" json:"systemReserved,omitempty"`
+	EvictionHard                     map[string]string `yaml:"evictionHard" json:"evictionHard,omitempty"`
+	EvictionSoft                     map[string]string `yaml:"evictionSoft" json:"evictionSoft,omitempty"`
+	EvictionSoftGracePeriod          map[string]string `yaml:"evictionSoftGracePeriod" json:"evictionSoftGracePeriod,omitempty"`
+	EvictionMaxPodGracePeriod        int               `yaml:"evictionMaxPodGracePeriod" json:"evictionMaxPodGracePeriod,omitempty"`
+	EvictionPressureTransitionPeriod string            `yaml:"evictionPressureTransitionPeriod" json:"evictionPressureTransitionPeriod,omitempty"`
+	ContainerLogMaxSize              string            `yaml:"containerLogMaxSize" json:"containerLogMaxSize,omitempty"`
+	ContainerLogMaxFiles             int               `yaml:"containerLogMaxFiles" json:"containerLogMaxFiles,omitempty"`
+	ServerTLSBootstrap               bool              `yaml:"serverTLSBootstrap" json:"serverTLSBootstrap,omitempty"`
+}
+
+// KubeletPool defines the kubelet configuration of the hosts given by name or selected by labels.
+type KubeletPool struct {
+	Name          string            `yaml:"name" json:"name,omitempty"`
+	Hosts         []string          `yaml:"hosts" json:"hosts,omitempty"`
+	Labels        map[string]string `yaml:"labels" json:"labels,omitempty"`
+	KubeletConfig `yaml:",inline" json:",inline"`
+}
+
+// KubeadmConfigPatch defines a patch applied to the documents of the given kind in the kubeadm configuration.
+type KubeadmConfigPatch struct {
+	Kind  string `yaml:"kind" json:"kind"`
+	Type  string `yaml:"type" json:"type,omitempty"`
+	Patch string `yaml:"patch" json:"patch"`
+}
+
+// HostPathMount defines a volume mounted from the host into a static pod of the control plane.
+type HostPathMount struct {
+	Name      string `yaml:"name" json:"name"`
+	HostPath  string `yaml:"hostPath" json:"hostPath"`
+	MountPath string `yaml:"mountPath" json:"mountPath"`
+	ReadOnly  bool   `yaml:"readOnly" json:"readOnly,omitempty"`
+	PathType  string `yaml:"pathType" json:"pathType,omitempty"`
 }
@@ -16,6 +16,11 @@ limitations under the License.
 
 package v1alpha1
 
+import (
+	"net"
+	"strings"
+)
+
 type NetworkConfig struct {
 	Plugin          string     `yaml:"plugin" json:"plugin,omitempty"`
 	KubePodsCIDR    string     `yaml:"kubePodsCIDR" json:"kubePodsCIDR,omitempty"`
@@ -50,3 +55,47 @@ type KubeovnCfg struct {
 	PingerExternalAddress string `yaml:"pingerExternalAddress" json:"pingerExternalAddress,omitempty"`
 	PingerExternalDomain  string `yaml:"pingerExternalDomain" json:"pingerExternalDomain,omitempty"`
 }
+
+// PodsCIDRs returns the CIDRs of pods, there are an IPv4 and an IPv6 one in a dual-stack cluster.
+func (n *NetworkConfig) PodsCIDRs() []string {
+	return splitCIDRs(n.KubePodsCIDR)
+}
+
+// ServiceCIDRs returns the CIDRs of services, there are an IPv4 and an IPv6 one in a dual-stack cluster.
+func (n *NetworkConfig) ServiceCIDRs() []string {
+	return splitCIDRs(n.KubeServiceCIDR)
+}
+
+// DualStack returns whether the cluster has both IPv4 and IPv6 addresses.
+func (n *NetworkConfig) DualStack() bool {
+	return len(n.PodsCIDRs()) > 1
+}
+
+// PodsCIDRIPv4 returns the IPv4 CIDR of pods, it is empty in an IPv6 single-stack cluster.
+func (n *NetworkConfig) PodsCIDRIPv4() string {
+	return cidrOfFamily(n.PodsCIDRs(), false)
+}
+
+// PodsCIDRIPv6 returns the IPv6 CIDR of pods, it is empty in an IPv4 single-stack cluster.
+func (n *NetworkConfig) PodsCIDRIPv6() string {
+	return cidrOfFamily(n.PodsCIDRs(), true)
+}
+
+func splitCIDRs(cidrs string) []string {
+	var list []string
+	for _, cidr := range strings.Split(cidrs, ",") {
+		if cidr = strings.TrimSpace(cidr); cidr != "" {
+			list = append(list, cidr)
+		}
+	}
+	return list
+}
+
+func cidrOfFamily(cidrs []string, ipv6 bool) string {
+	for _, cidr := range cidrs {
+		if ip, _, err := net.ParseCIDR(cidr); err == nil && (ip.To4() == nil) == ipv6 {
+			return cidr
+		}
+	}
+	return ""
+}
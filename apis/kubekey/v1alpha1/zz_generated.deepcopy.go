@@ -1,3 +1,4 @@
+//go:build !ignore_autogenerated
 // +build !ignore_autogenerated
 
 /*
@@ -21,6 +22,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -40,6 +42,100 @@ func (in *Addon) DeepCopy() *Addon {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Admission) DeepCopyInto(out *Admission) {
+	*out = *in
+	if in.EnablePlugins != nil {
+		in, out := &in.EnablePlugins, &out.EnablePlugins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DisablePlugins != nil {
+		in, out := &in.DisablePlugins, &out.DisablePlugins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PluginConfigs != nil {
+		in, out := &in.PluginConfigs, &out.PluginConfigs
+		*out = make([]AdmissionPluginConfig, len(*in))
+		copy(*out, *in)
+	}
+	in.PodSecurity.DeepCopyInto(&out.PodSecurity)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Admission.
+func (in *Admission) DeepCopy() *Admission {
+	if in == nil {
+		return nil
+	}
+	out := new(Admission)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdmissionPluginConfig) DeepCopyInto(out *AdmissionPluginConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdmissionPluginConfig.
+func (in *AdmissionPluginConfig) DeepCopy() *AdmissionPluginConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AdmissionPluginConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Audit) DeepCopyInto(out *Audit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Audit.
+func (in *Audit) DeepCopy() *Audit {
+	if in == nil {
+		return nil
+	}
+	out := new(Audit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Authentication) DeepCopyInto(out *Authentication) {
+	*out = *in
+	in.OIDC.DeepCopyInto(&out.OIDC)
+	out.Webhook = in.Webhook
+	out.AuthorizationWebhook = in.AuthorizationWebhook
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Authentication.
+func (in *Authentication) DeepCopy() *Authentication {
+	if in == nil {
+		return nil
+	}
+	out := new(Authentication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthorizationWebhook) DeepCopyInto(out *AuthorizationWebhook) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorizationWebhook.
+func (in *AuthorizationWebhook) DeepCopy() *AuthorizationWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthorizationWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CalicoCfg) DeepCopyInto(out *CalicoCfg) {
 	*out = *in
@@ -140,10 +236,13 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 	if in.Hosts != nil {
 		in, out := &in.Hosts, &out.Hosts
 		*out = make([]HostCfg, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	in.RoleGroups.DeepCopyInto(&out.RoleGroups)
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
+	in.Etcd.DeepCopyInto(&out.Etcd)
 	in.Kubernetes.DeepCopyInto(&out.Kubernetes)
 	out.Network = in.Network
 	in.Registry.DeepCopyInto(&out.Registry)
@@ -244,6 +343,42 @@ func (in *ControlPlaneEndpoint) DeepCopy() *ControlPlaneEndpoint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Encryption) DeepCopyInto(out *Encryption) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Encryption.
+func (in *Encryption) DeepCopy() *Encryption {
+	if in == nil {
+		return nil
+	}
+	out := new(Encryption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdCluster) DeepCopyInto(out *EtcdCluster) {
+	*out = *in
+	in.External.DeepCopyInto(&out.External)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdCluster.
+func (in *EtcdCluster) DeepCopy() *EtcdCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalEtcd) DeepCopyInto(out *ExternalEtcd) {
 	*out = *in
@@ -282,6 +417,25 @@ func (in *FlannelCfg) DeepCopy() *FlannelCfg {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostCfg) DeepCopyInto(out *HostCfg) {
 	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]v1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Kubelet != nil {
+		in, out := &in.Kubelet, &out.Kubelet
+		*out = new(KubeletConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostCfg.
@@ -294,33 +448,65 @@ func (in *HostCfg) DeepCopy() *HostCfg {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostPathMount) DeepCopyInto(out *HostPathMount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostPathMount.
+func (in *HostPathMount) DeepCopy() *HostPathMount {
+	if in == nil {
+		return nil
+	}
+	out := new(HostPathMount)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostGroups) DeepCopyInto(out *HostGroups) {
 	*out = *in
 	if in.All != nil {
 		in, out := &in.All, &out.All
 		*out = make([]HostCfg, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Etcd != nil {
 		in, out := &in.Etcd, &out.Etcd
 		*out = make([]HostCfg, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Master != nil {
 		in, out := &in.Master, &out.Master
 		*out = make([]HostCfg, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Worker != nil {
 		in, out := &in.Worker, &out.Worker
 		*out = make([]HostCfg, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.K8s != nil {
 		in, out := &in.K8s, &out.K8s
 		*out = make([]HostCfg, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LoadBalancer != nil {
+		in, out := &in.LoadBalancer, &out.LoadBalancer
+		*out = make([]HostCfg, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -356,6 +542,74 @@ func (in *JobInfo) DeepCopy() *JobInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeProxy) DeepCopyInto(out *KubeProxy) {
+	*out = *in
+	in.IPVS.DeepCopyInto(&out.IPVS)
+	out.IPTables = in.IPTables
+	out.Conntrack = in.Conntrack
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeProxy.
+func (in *KubeProxy) DeepCopy() *KubeProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeProxyConntrack) DeepCopyInto(out *KubeProxyConntrack) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeProxyConntrack.
+func (in *KubeProxyConntrack) DeepCopy() *KubeProxyConntrack {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeProxyConntrack)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeProxyIPTables) DeepCopyInto(out *KubeProxyIPTables) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeProxyIPTables.
+func (in *KubeProxyIPTables) DeepCopy() *KubeProxyIPTables {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeProxyIPTables)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeProxyIPVS) DeepCopyInto(out *KubeProxyIPVS) {
+	*out = *in
+	if in.ExcludeCIDRs != nil {
+		in, out := &in.ExcludeCIDRs, &out.ExcludeCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeProxyIPVS.
+func (in *KubeProxyIPVS) DeepCopy() *KubeProxyIPVS {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeProxyIPVS)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubeSphere) DeepCopyInto(out *KubeSphere) {
 	*out = *in
@@ -371,6 +625,99 @@ func (in *KubeSphere) DeepCopy() *KubeSphere {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeadmConfigPatch) DeepCopyInto(out *KubeadmConfigPatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeadmConfigPatch.
+func (in *KubeadmConfigPatch) DeepCopy() *KubeadmConfigPatch {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeadmConfigPatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeletConfig) DeepCopyInto(out *KubeletConfig) {
+	*out = *in
+	if in.KubeReserved != nil {
+		in, out := &in.KubeReserved, &out.KubeReserved
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SystemReserved != nil {
+		in, out := &in.SystemReserved, &out.SystemReserved
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.EvictionHard != nil {
+		in, out := &in.EvictionHard, &out.EvictionHard
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.EvictionSoft != nil {
+		in, out := &in.EvictionSoft, &out.EvictionSoft
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.EvictionSoftGracePeriod != nil {
+		in, out := &in.EvictionSoftGracePeriod, &out.EvictionSoftGracePeriod
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletConfig.
+func (in *KubeletConfig) DeepCopy() *KubeletConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeletConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeletPool) DeepCopyInto(out *KubeletPool) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.KubeletConfig.DeepCopyInto(&out.KubeletConfig)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletPool.
+func (in *KubeletPool) DeepCopy() *KubeletPool {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeletPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubeovnCfg) DeepCopyInto(out *KubeovnCfg) {
 	*out = *in
@@ -394,6 +741,75 @@ func (in *Kubernetes) DeepCopyInto(out *Kubernetes) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ApiServerArgs != nil {
+		in, out := &in.ApiServerArgs, &out.ApiServerArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ControllerManagerArgs != nil {
+		in, out := &in.ControllerManagerArgs, &out.ControllerManagerArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SchedulerArgs != nil {
+		in, out := &in.SchedulerArgs, &out.SchedulerArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.KubeProxyArgs != nil {
+		in, out := &in.KubeProxyArgs, &out.KubeProxyArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ApiServerExtraVolumes != nil {
+		in, out := &in.ApiServerExtraVolumes, &out.ApiServerExtraVolumes
+		*out = make([]HostPathMount, len(*in))
+		copy(*out, *in)
+	}
+	if in.ControllerManagerExtraVolumes != nil {
+		in, out := &in.ControllerManagerExtraVolumes, &out.ControllerManagerExtraVolumes
+		*out = make([]HostPathMount, len(*in))
+		copy(*out, *in)
+	}
+	if in.SchedulerExtraVolumes != nil {
+		in, out := &in.SchedulerExtraVolumes, &out.SchedulerExtraVolumes
+		*out = make([]HostPathMount, len(*in))
+		copy(*out, *in)
+	}
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.KubeadmConfigPatches != nil {
+		in, out := &in.KubeadmConfigPatches, &out.KubeadmConfigPatches
+		*out = make([]KubeadmConfigPatch, len(*in))
+		copy(*out, *in)
+	}
+	in.Kubelet.DeepCopyInto(&out.Kubelet)
+	if in.KubeletPools != nil {
+		in, out := &in.KubeletPools, &out.KubeletPools
+		*out = make([]KubeletPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Audit = in.Audit
+	in.Encryption.DeepCopyInto(&out.Encryption)
+	in.Authentication.DeepCopyInto(&out.Authentication)
+	in.Admission.DeepCopyInto(&out.Admission)
+	in.CloudControllerManager.DeepCopyInto(&out.CloudControllerManager)
+	in.KubeProxy.DeepCopyInto(&out.KubeProxy)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kubernetes.
@@ -446,6 +862,33 @@ func (in *NodeStatus) DeepCopy() *NodeStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDC) DeepCopyInto(out *OIDC) {
+	*out = *in
+	if in.RequiredClaims != nil {
+		in, out := &in.RequiredClaims, &out.RequiredClaims
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SigningAlgs != nil {
+		in, out := &in.SigningAlgs, &out.SigningAlgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDC.
+func (in *OIDC) DeepCopy() *OIDC {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDC)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodInfo) DeepCopyInto(out *PodInfo) {
 	*out = *in
@@ -466,6 +909,36 @@ func (in *PodInfo) DeepCopy() *PodInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodSecurity) DeepCopyInto(out *PodSecurity) {
+	*out = *in
+	if in.ExemptNamespaces != nil {
+		in, out := &in.ExemptNamespaces, &out.ExemptNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExemptUsernames != nil {
+		in, out := &in.ExemptUsernames, &out.ExemptUsernames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExemptRuntimeClasses != nil {
+		in, out := &in.ExemptRuntimeClasses, &out.ExemptRuntimeClasses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSecurity.
+func (in *PodSecurity) DeepCopy() *PodSecurity {
+	if in == nil {
+		return nil
+	}
+	out := new(PodSecurity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RegistryConfig) DeepCopyInto(out *RegistryConfig) {
 	*out = *in
@@ -509,6 +982,11 @@ func (in *RoleGroups) DeepCopyInto(out *RoleGroups) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.LoadBalancer != nil {
+		in, out := &in.LoadBalancer, &out.LoadBalancer
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleGroups.
@@ -538,6 +1016,21 @@ func (in *Sources) DeepCopy() *Sources {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenWebhook) DeepCopyInto(out *TokenWebhook) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenWebhook.
+func (in *TokenWebhook) DeepCopy() *TokenWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Yaml) DeepCopyInto(out *Yaml) {
 	*out = *in
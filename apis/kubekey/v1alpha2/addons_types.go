@@ -0,0 +1,43 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+type Addon struct {
+	Name      string  `json:"name,omitempty" description:"The name of the addon."`
+	Namespace string  `json:"namespace,omitempty" description:"The namespace the addon is installed to."`
+	Sources   Sources `json:"sources,omitempty" description:"Where the addon is installed from, a helm chart or yaml files."`
+	Retries   int     `json:"retries,omitempty" description:"The number of retries when the installation fails."`
+	Delay     int     `json:"delay,omitempty" description:"The seconds to wait between retries."`
+}
+
+type Sources struct {
+	Chart Chart `json:"chart,omitempty"`
+	Yaml  Yaml  `json:"yaml,omitempty"`
+}
+
+type Chart struct {
+	Name       string   `json:"name,omitempty"`
+	Repo       string   `json:"repo,omitempty"`
+	Path       string   `json:"path,omitempty"`
+	Version    string   `json:"version,omitempty"`
+	ValuesFile string   `json:"valuesFile,omitempty"`
+	Values     []string `json:"values,omitempty"`
+}
+
+type Yaml struct {
+	Path []string `json:"path,omitempty"`
+}
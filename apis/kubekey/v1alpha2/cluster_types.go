@@ -0,0 +1,163 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ClusterSpec defines the desired state of Cluster
+type ClusterSpec struct {
+	Hosts                []HostCfg            `json:"hosts,omitempty" description:"The hosts of the cluster and how to connect to them."`
+	RoleGroups           RoleGroups           `json:"roleGroups,omitempty" description:"The roles of hosts, hosts are referred to by name, ranges like node[1:3] are supported."`
+	ControlPlaneEndpoint ControlPlaneEndpoint `json:"controlPlaneEndpoint,omitempty" description:"The endpoint of kube-apiserver."`
+	Etcd                 EtcdCluster          `json:"etcd,omitempty" description:"How etcd of the cluster is deployed."`
+	Kubernetes           Kubernetes           `json:"kubernetes,omitempty" description:"The configuration of kubernetes components."`
+	Network              NetworkConfig        `json:"network,omitempty" description:"The configuration of the cluster network."`
+	Registry             RegistryConfig       `json:"registry,omitempty" description:"The configuration of image registries."`
+	Addons               []Addon              `json:"addons,omitempty" description:"The addons installed after the cluster is created."`
+	KubeSphere           KubeSphere           `json:"kubesphere,omitempty" description:"The configuration of KubeSphere."`
+}
+
+// ClusterStatus defines the observed state of Cluster
+type ClusterStatus struct {
+	JobInfo       JobInfo      `json:"jobInfo,omitempty"`
+	Version       string       `json:"version,omitempty"`
+	NetworkPlugin string       `json:"networkPlugin,omitempty"`
+	NodesCount    int          `json:"nodesCount,omitempty"`
+	EtcdCount     int          `json:"etcdCount,omitempty"`
+	MasterCount   int          `json:"masterCount,omitempty"`
+	WorkerCount   int          `json:"workerCount,omitempty"`
+	Nodes         []NodeStatus `json:"nodes,omitempty"`
+	Conditions    []Condition  `json:"conditions,omitempty"`
+}
+
+type JobInfo struct {
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Pods      []PodInfo `json:"pods,omitempty"`
+}
+
+type PodInfo struct {
+	Name       string          `json:"name,omitempty"`
+	Containers []ContainerInfo `json:"containers,omitempty"`
+}
+
+type ContainerInfo struct {
+	Name string `json:"name,omitempty"`
+}
+
+type NodeStatus struct {
+	InternalIP string          `json:"internalIP,omitempty"`
+	Hostname   string          `json:"hostname,omitempty"`
+	Roles      map[string]bool `json:"roles,omitempty"`
+}
+
+type Condition struct {
+	Step      string      `json:"step,omitempty"`
+	StartTime metav1.Time `json:"startTime,omitempty"`
+	EndTime   metav1.Time `json:"endTime,omitempty"`
+	Status    bool        `json:"status,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+
+// Cluster is the Schema for the clusters API
+// +kubebuilder:resource:path=clusters,scope=Cluster
+type Cluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterSpec   `json:"spec,omitempty"`
+	Status ClusterStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterList contains a list of Cluster
+type ClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Cluster `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Cluster{}, &ClusterList{})
+}
+
+// HostCfg describes a host of the cluster and how to connect to it.
+type HostCfg struct {
+	Name            string            `json:"name,omitempty" description:"The hostname of the host."`
+	Address         string            `json:"address,omitempty" description:"The address used to connect to the host by ssh."`
+	InternalAddress string            `json:"internalAddress,omitempty" description:"The address used for the communication inside the cluster."`
+	InternalIPv6    string            `json:"internalIPv6,omitempty" description:"The IPv6 address used for the communication inside a dual-stack cluster."`
+	Port            int               `json:"port,omitempty" description:"The ssh port. [Default: 22]"`
+	User            string            `json:"user,omitempty" description:"The ssh user. [Default: root]"`
+	Password        string            `json:"password,omitempty" description:"The ssh password."`
+	PrivateKey      string            `json:"privateKey,omitempty" description:"The content of the ssh private key."`
+	PrivateKeyPath  string            `json:"privateKeyPath,omitempty" description:"The path of the ssh private key. [Default: ~/.ssh/id_rsa]"`
+	Arch            string            `json:"arch,omitempty" description:"The cpu architecture of the host. [Default: amd64]" enum:"amd64,arm64"`
+	Labels          map[string]string `json:"labels,omitempty" description:"The labels of the kubernetes node."`
+	Taints          []corev1.Taint    `json:"taints,omitempty" description:"The taints of the kubernetes node."`
+	Kubelet         *KubeletConfig    `json:"kubelet,omitempty" description:"The kubelet configuration of the host, it takes precedence over the cluster and the kubeletPools."`
+}
+
+type RoleGroups struct {
+	Etcd         []string `json:"etcd,omitempty" description:"The hosts running etcd."`
+	Master       []string `json:"master,omitempty" description:"The hosts running the kubernetes control plane."`
+	Worker       []string `json:"worker,omitempty" description:"The hosts running workloads."`
+	LoadBalancer []string `json:"loadbalancer,omitempty" description:"The hosts running keepalived and haproxy as the load balancer of kube-apiserver, the address of the control plane endpoint is used as the VIP."`
+}
+
+type ControlPlaneEndpoint struct {
+	Domain               string `json:"domain,omitempty" description:"The domain name of kube-apiserver. [Default: lb.kubesphere.local]"`
+	Address              string `json:"address,omitempty" description:"The address of the load balancer of kube-apiserver, it is required when there are at least three masters and no internal load balancer, or when the internal load balancer is kube-vip."`
+	Port                 int    `json:"port,omitempty" description:"The port of kube-apiserver. [Default: 6443]"`
+	InternalLoadbalancer string `json:"internalLoadbalancer,omitempty" description:"The load balancer deployed by KubeKey if there is no external one, haproxy runs a proxy to all the kube-apiservers on every worker, kube-vip holds the address as a VIP on the masters." enum:"haproxy,kube-vip"`
+}
+
+type EtcdCluster struct {
+	Type     string       `json:"type,omitempty" description:"The type of etcd, kubekey runs etcd as a service on the etcd hosts, kubeadm runs etcd as static pods on the masters, external uses an etcd cluster which is not managed by KubeKey. [Default: kubekey]" enum:"kubekey,kubeadm,external"`
+	External ExternalEtcd `json:"external,omitempty" description:"The external etcd cluster, it is required when the type is external."`
+}
+
+type ExternalEtcd struct {
+	Endpoints []string `json:"endpoints,omitempty" description:"The client URLs of the etcd members, e.g. https://192.168.0.10:2379."`
+	CaFile    string   `json:"caFile,omitempty" description:"The local path of the CA certificate of etcd, it is copied to the masters."`
+	CertFile  string   `json:"certFile,omitempty" description:"The local path of the client certificate of etcd, it is copied to the masters."`
+	KeyFile   string   `json:"keyFile,omitempty" description:"The local path of the client key of etcd, it is copied to the masters."`
+}
+
+type RegistryConfig struct {
+	RegistryMirrors    []string `json:"registryMirrors,omitempty" description:"The mirrors of docker hub."`
+	InsecureRegistries []string `json:"insecureRegistries,omitempty" description:"The registries accessed by http or with untrusted certificates."`
+	PrivateRegistry    string   `json:"privateRegistry,omitempty" description:"The registry where all the images are pulled from."`
+}
+
+type KubeSphere struct {
+	Enabled bool   `json:"enabled,omitempty" description:"Whether to install KubeSphere."`
+	Version string `json:"version,omitempty" description:"The version of KubeSphere."`
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Configurations *runtime.RawExtension `json:"configurations,omitempty" description:"The ks-installer object (the ClusterConfiguration of v3.x or the ConfigMap of v2.x)."`
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var clusterlog = logf.Log.WithName("cluster-resource")
+
+// SetupWebhookWithManager registers the defaulting webhook of Cluster, and the conversion webhook as Cluster of v1alpha2 is the hub.
+func (r *Cluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-kubekey-kubesphere-io-v1alpha2-cluster,mutating=true,failurePolicy=fail,groups=kubekey.kubesphere.io,resources=clusters,verbs=create;update,versions=v1alpha2,name=mcluster.kb.io
+
+var _ webhook.Defaulter = &Cluster{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (r *Cluster) Default() {
+	clusterlog.Info("default", "name", r.Name)
+
+	SetDefaultClusterSpec(&r.Spec)
+}
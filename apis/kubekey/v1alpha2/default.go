@@ -0,0 +1,305 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+const (
+	DefaultPreDir              = "kubekey"
+	DefaultSSHPort             = 22
+	DefaultLBPort              = 6443
+	DefaultLBDomain            = "lb.kubesphere.local"
+	DefaultNetworkPlugin       = "calico"
+	DefaultPodsCIDR            = "10.233.64.0/18"
+	DefaultServiceCIDR         = "10.233.0.0/18"
+	DefaultKubeImageNamespace  = "kubesphere"
+	DefaultClusterName         = "cluster.local"
+	DefaultArch                = "amd64"
+	DefaultEtcdVersion         = "v3.4.13"
+	DefaultEtcdPort            = "2379"
+	DefaultKubeVersion         = "v1.17.9"
+	DefaultCalicoVersion       = "v3.16.3"
+	DefaultFlannelVersion      = "v0.12.0"
+	DefaultCniVersion          = "v0.8.6"
+	DefaultCiliumVersion       = "v1.8.3"
+	DefaultKubeovnVersion      = "v1.5.0"
+	DefaultHelmVersion         = "v3.2.1"
+	DefaultMaxPods             = 110
+	DefaultNodeCidrMaskSize    = 24
+	DefaultIPIPMode            = "Always"
+	DefaultVXLANMode           = "Never"
+	DefaultVethMTU             = 1440
+	DefaultBackendMode         = "vxlan"
+	DefaultProxyMode           = "ipvs"
+	DefaultCrioEndpoint        = "unix:///var/run/crio/crio.sock"
+	DefaultContainerdEndpoint  = "unix:///run/containerd/containerd.sock"
+	DefaultIsulaEndpoint       = "unix:///var/run/isulad.sock"
+	DefaultEtcdBackupDir       = "/var/backups/kube_etcd"
+	DefaultEtcdBackupPeriod    = 30
+	DefaultKeepBackNumber      = 5
+	DefaultEtcdBackupScriptDir = "/usr/local/bin/kube-scripts"
+	DefaultJoinCIDR            = "100.64.0.0/16"
+	DefaultNetworkType         = "geneve"
+	DefaultVlanID              = "100"
+	DefaultOvnLabel            = "node-role.kubernetes.io/master"
+	DefaultDPDKVersion         = "19.11"
+	DefaultDNSAddress          = "114.114.114.114"
+	DefaultPrivateKeyPath      = "~/.ssh/id_rsa"
+
+	DefaultReservedCPU                      = "200m"
+	DefaultReservedMemory                   = "250Mi"
+	DefaultEvictionHardMemory               = "5%"
+	DefaultEvictionSoftMemory               = "10%"
+	DefaultEvictionSoftGracePeriod          = "2m"
+	DefaultEvictionMaxPodGracePeriod        = 120
+	DefaultEvictionPressureTransitionPeriod = "30s"
+	DefaultContainerLogMaxSize              = "5Mi"
+	DefaultContainerLogMaxFiles             = 3
+
+	DefaultAuditBackend      = "log"
+	DefaultAuditLogPath      = "/var/log/apiserver/audit.log"
+	DefaultAuditLogMaxAge    = 30
+	DefaultAuditLogMaxBackup = 10
+	DefaultAuditLogMaxSize   = 100
+
+	DefaultEncryptionProvider = "aescbc"
+
+	// the PodSecurity levels not given are privileged, which allows everything
+	DefaultPodSecurityLevel   = "privileged"
+	DefaultPodSecurityVersion = "latest"
+
+	DefaultIPVSScheduler          = "rr"
+	DefaultKubeProxySyncPeriod    = "30s"
+	DefaultKubeProxyMinSyncPeriod = "0s"
+	DefaultMasqueradeBit          = 14
+	DefaultConntrackMaxPerCore    = 32768
+	DefaultConntrackMin           = 131072
+	DefaultTCPEstablishedTimeout  = "24h0m0s"
+	DefaultTCPCloseWaitTimeout    = "1h0m0s"
+
+	// CloudProviderExternal means the cloud provider is integrated by an external cloud-controller-manager.
+	CloudProviderExternal = "external"
+
+	DefaultNodeCidrMaskSizeIPv6 = 64
+
+	EtcdTypeKubeKey  = "kubekey"
+	EtcdTypeKubeadm  = "kubeadm"
+	EtcdTypeExternal = "external"
+
+	InternalLoadbalancerHaproxy = "haproxy"
+	InternalLoadbalancerKubeVip = "kube-vip"
+)
+
+// SetDefaultClusterSpec fills in the default values of the cluster spec in place.
+// It only depends on the spec itself, so it is shared by kk and the defaulting webhook of the operator.
+// The defaults which depend on the role of hosts (e.g. the address of the control plane endpoint) are set by kk after grouping hosts.
+func SetDefaultClusterSpec(cfg *ClusterSpec) {
+	SetDefaultHostsCfg(cfg)
+	SetDefaultLBCfg(cfg)
+	SetDefaultEtcdCfg(cfg)
+	SetDefaultNetworkCfg(cfg)
+	SetDefaultClusterCfg(cfg)
+}
+
+func SetDefaultHostsCfg(cfg *ClusterSpec) {
+	for i := range cfg.Hosts {
+		host := &cfg.Hosts[i]
+		if len(host.Address) == 0 && len(host.InternalAddress) > 0 {
+			host.Address = host.InternalAddress
+		}
+		if len(host.InternalAddress) == 0 && len(host.Address) > 0 {
+			host.InternalAddress = host.Address
+		}
+		if host.User == "" {
+			host.User = "root"
+		}
+		if host.Port == 0 {
+			host.Port = DefaultSSHPort
+		}
+		if host.PrivateKey == "" && host.Password == "" && host.PrivateKeyPath == "" {
+			host.PrivateKeyPath = DefaultPrivateKeyPath
+		}
+		if host.Arch == "" {
+			host.Arch = DefaultArch
+		}
+	}
+}
+
+func SetDefaultLBCfg(cfg *ClusterSpec) {
+	if cfg.ControlPlaneEndpoint.Domain == "" {
+		cfg.ControlPlaneEndpoint.Domain = DefaultLBDomain
+	}
+	if cfg.ControlPlaneEndpoint.Port == 0 {
+		cfg.ControlPlaneEndpoint.Port = DefaultLBPort
+	}
+}
+
+func SetDefaultEtcdCfg(cfg *ClusterSpec) {
+	if cfg.Etcd.Type == "" {
+		cfg.Etcd.Type = EtcdTypeKubeKey
+	}
+}
+
+func SetDefaultNetworkCfg(cfg *ClusterSpec) {
+	if cfg.Network.Plugin == "" {
+		cfg.Network.Plugin = DefaultNetworkPlugin
+	}
+	if cfg.Network.KubePodsCIDR == "" {
+		cfg.Network.KubePodsCIDR = DefaultPodsCIDR
+	}
+	if cfg.Network.KubeServiceCIDR == "" {
+		cfg.Network.KubeServiceCIDR = DefaultServiceCIDR
+	}
+	if cfg.Network.Calico.IPIPMode == "" {
+		cfg.Network.Calico.IPIPMode = DefaultIPIPMode
+	}
+	if cfg.Network.Calico.VXLANMode == "" {
+		cfg.Network.Calico.VXLANMode = DefaultVXLANMode
+	}
+	if cfg.Network.Calico.VethMTU == 0 {
+		cfg.Network.Calico.VethMTU = DefaultVethMTU
+	}
+	if cfg.Network.Flannel.BackendMode == "" {
+		cfg.Network.Flannel.BackendMode = DefaultBackendMode
+	}
+	// kube-ovn default config
+	if cfg.Network.Kubeovn.JoinCIDR == "" {
+		cfg.Network.Kubeovn.JoinCIDR = DefaultJoinCIDR
+	}
+	if cfg.Network.Kubeovn.Label == "" {
+		cfg.Network.Kubeovn.Label = DefaultOvnLabel
+	}
+	if cfg.Network.Kubeovn.VlanID == "" {
+		cfg.Network.Kubeovn.VlanID = DefaultVlanID
+	}
+	if cfg.Network.Kubeovn.NetworkType == "" {
+		cfg.Network.Kubeovn.NetworkType = DefaultNetworkType
+	}
+	if cfg.Network.Kubeovn.PingerExternalAddress == "" {
+		cfg.Network.Kubeovn.PingerExternalAddress = DefaultDNSAddress
+	}
+	if cfg.Network.Kubeovn.DpdkVersion == "" {
+		cfg.Network.Kubeovn.DpdkVersion = DefaultDPDKVersion
+	}
+}
+
+func SetDefaultClusterCfg(cfg *ClusterSpec) {
+	if cfg.Kubernetes.Version == "" {
+		cfg.Kubernetes.Version = DefaultKubeVersion
+	}
+	if cfg.Kubernetes.ClusterName == "" {
+		cfg.Kubernetes.ClusterName = DefaultClusterName
+	}
+	if cfg.Kubernetes.MaxPods == 0 {
+		cfg.Kubernetes.MaxPods = DefaultMaxPods
+	}
+	if cfg.Kubernetes.NodeCidrMaskSize == 0 {
+		cfg.Kubernetes.NodeCidrMaskSize = DefaultNodeCidrMaskSize
+	}
+	if cfg.Kubernetes.NodeCidrMaskSizeIPv6 == 0 {
+		cfg.Kubernetes.NodeCidrMaskSizeIPv6 = DefaultNodeCidrMaskSizeIPv6
+	}
+	if cfg.Kubernetes.ProxyMode == "" {
+		cfg.Kubernetes.ProxyMode = DefaultProxyMode
+	}
+	if cfg.Kubernetes.EtcdBackupDir == "" {
+		cfg.Kubernetes.EtcdBackupDir = DefaultEtcdBackupDir
+	}
+	if cfg.Kubernetes.EtcdBackupPeriod == 0 {
+		cfg.Kubernetes.EtcdBackupPeriod = DefaultEtcdBackupPeriod
+	}
+	if cfg.Kubernetes.KeepBackupNumber == 0 {
+		cfg.Kubernetes.KeepBackupNumber = DefaultKeepBackNumber
+	}
+	if cfg.Kubernetes.EtcdBackupScriptDir == "" {
+		cfg.Kubernetes.EtcdBackupScriptDir = DefaultEtcdBackupScriptDir
+	}
+	if cfg.Kubernetes.Audit.Backend == "" {
+		cfg.Kubernetes.Audit.Backend = DefaultAuditBackend
+	}
+	if cfg.Kubernetes.Audit.LogPath == "" {
+		cfg.Kubernetes.Audit.LogPath = DefaultAuditLogPath
+	}
+	if cfg.Kubernetes.Audit.LogMaxAge == 0 {
+		cfg.Kubernetes.Audit.LogMaxAge = DefaultAuditLogMaxAge
+	}
+	if cfg.Kubernetes.Audit.LogMaxBackup == 0 {
+		cfg.Kubernetes.Audit.LogMaxBackup = DefaultAuditLogMaxBackup
+	}
+	if cfg.Kubernetes.Audit.LogMaxSize == 0 {
+		cfg.Kubernetes.Audit.LogMaxSize = DefaultAuditLogMaxSize
+	}
+	if cfg.Kubernetes.Encryption.Provider == "" {
+		cfg.Kubernetes.Encryption.Provider = DefaultEncryptionProvider
+	}
+	if len(cfg.Kubernetes.Encryption.Resources) == 0 {
+		cfg.Kubernetes.Encryption.Resources = []string{"secrets"}
+	}
+	if podSecurity := &cfg.Kubernetes.Admission.PodSecurity; podSecurity.Enforce != "" || podSecurity.Audit != "" || podSecurity.Warn != "" {
+		for _, level := range []*string{&podSecurity.Enforce, &podSecurity.Audit, &podSecurity.Warn} {
+			if *level == "" {
+				*level = DefaultPodSecurityLevel
+			}
+		}
+		for _, version := range []*string{&podSecurity.EnforceVersion, &podSecurity.AuditVersion, &podSecurity.WarnVersion} {
+			if *version == "" {
+				*version = DefaultPodSecurityVersion
+			}
+		}
+		if podSecurity.ExemptNamespaces == nil {
+			podSecurity.ExemptNamespaces = []string{"kube-system"}
+		}
+	}
+	SetDefaultKubeProxyCfg(&cfg.Kubernetes.KubeProxy)
+	if ccm := &cfg.Kubernetes.CloudControllerManager; cfg.Kubernetes.CloudProvider == CloudProviderExternal {
+		if ccm.Name == "" {
+			ccm.Name = "cloud-controller-manager"
+		}
+		if ccm.Namespace == "" {
+			ccm.Namespace = "kube-system"
+		}
+	}
+}
+
+func SetDefaultKubeProxyCfg(kubeProxy *KubeProxy) {
+	if kubeProxy.IPVS.Scheduler == "" {
+		kubeProxy.IPVS.Scheduler = DefaultIPVSScheduler
+	}
+	for _, period := range []*string{&kubeProxy.IPVS.SyncPeriod, &kubeProxy.IPTables.SyncPeriod} {
+		if *period == "" {
+			*period = DefaultKubeProxySyncPeriod
+		}
+	}
+	for _, period := range []*string{&kubeProxy.IPVS.MinSyncPeriod, &kubeProxy.IPTables.MinSyncPeriod} {
+		if *period == "" {
+			*period = DefaultKubeProxyMinSyncPeriod
+		}
+	}
+	if kubeProxy.IPTables.MasqueradeBit == 0 {
+		kubeProxy.IPTables.MasqueradeBit = DefaultMasqueradeBit
+	}
+	if kubeProxy.Conntrack.MaxPerCore == 0 {
+		kubeProxy.Conntrack.MaxPerCore = DefaultConntrackMaxPerCore
+	}
+	if kubeProxy.Conntrack.Min == 0 {
+		kubeProxy.Conntrack.Min = DefaultConntrackMin
+	}
+	if kubeProxy.Conntrack.TCPEstablishedTimeout == "" {
+		kubeProxy.Conntrack.TCPEstablishedTimeout = DefaultTCPEstablishedTimeout
+	}
+	if kubeProxy.Conntrack.TCPCloseWaitTimeout == "" {
+		kubeProxy.Conntrack.TCPCloseWaitTimeout = DefaultTCPCloseWaitTimeout
+	}
+}
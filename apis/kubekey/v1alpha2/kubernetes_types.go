@@ -0,0 +1,213 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+type Kubernetes struct {
+	Version                       string               `json:"version,omitempty" description:"The version of kubernetes. [Default: v1.17.9]"`
+	ClusterName                   string               `json:"clusterName,omitempty" description:"The dns domain of the cluster. [Default: cluster.local]"`
+	MasqueradeAll                 bool                 `json:"masqueradeAll,omitempty" description:"Tells kube-proxy to SNAT everything if using the pure iptables proxy mode. [Default: false]"`
+	MaxPods                       int                  `json:"maxPods,omitempty" description:"The number of pods that can run on a node. [Default: 110]"`
+	NodeCidrMaskSize              int                  `json:"nodeCidrMaskSize,omitempty" description:"The mask size of the pod CIDR allocated to each node. [Default: 24]"`
+	NodeCidrMaskSizeIPv6          int                  `json:"nodeCidrMaskSizeIPv6,omitempty" description:"The mask size of the IPv6 pod CIDR allocated to each node in a dual-stack cluster. [Default: 64]"`
+	ApiserverCertExtraSans        []string             `json:"apiserverCertExtraSans,omitempty" description:"The extra subject alternative names of the certificate of kube-apiserver."`
+	ProxyMode                     string               `json:"proxyMode,omitempty" description:"The proxy mode of kube-proxy. [Default: ipvs]" enum:"ipvs,iptables"`
+	EtcdBackupDir                 string               `json:"etcdBackupDir,omitempty" description:"The directory where etcd is backed up. [Default: /var/backups/kube_etcd]"`
+	EtcdBackupPeriod              int                  `json:"etcdBackupPeriod,omitempty" description:"The period of etcd backups in minutes. [Default: 30]"`
+	KeepBackupNumber              int                  `json:"keepBackupNumber,omitempty" description:"The number of etcd backups to keep. [Default: 5]"`
+	EtcdBackupScriptDir           string               `json:"etcdBackupScript,omitempty" description:"The directory of the etcd backup script. [Default: /usr/local/bin/kube-scripts]"`
+	ContainerManager              string               `json:"containerManager,omitempty" description:"The container runtime, docker is not supported by kubernetes v1.24+. [Default: docker]" enum:"docker,crio,containerd,isula"`
+	ContainerRuntimeEndpoint      string               `json:"containerRuntimeEndpoint,omitempty" description:"The endpoint of the container runtime, it is not required for docker."`
+	ApiServerArgs                 map[string]string    `json:"apiserverArgs,omitempty" description:"The extra flags of kube-apiserver, they are merged over the default flags."`
+	ControllerManagerArgs         map[string]string    `json:"controllerManagerArgs,omitempty" description:"The extra flags of kube-controller-manager, they are merged over the default flags."`
+	SchedulerArgs                 map[string]string    `json:"schedulerArgs,omitempty" description:"The extra flags of kube-scheduler, they are merged over the default flags."`
+	KubeProxyArgs                 map[string]string    `json:"kubeProxyArgs,omitempty" description:"The extra flags of kube-proxy."`
+	ApiServerExtraVolumes         []HostPathMount      `json:"apiserverExtraVolumes,omitempty" description:"The extra volumes mounted from the host into kube-apiserver."`
+	ControllerManagerExtraVolumes []HostPathMount      `json:"controllerManagerExtraVolumes,omitempty" description:"The extra volumes mounted from the host into kube-controller-manager."`
+	SchedulerExtraVolumes         []HostPathMount      `json:"schedulerExtraVolumes,omitempty" description:"The extra volumes mounted from the host into kube-scheduler."`
+	FeatureGates                  map[string]bool      `json:"featureGates,omitempty" description:"The feature gates of all the components, they are merged over the default feature gates."`
+	KubeadmConfigPatches          []KubeadmConfigPatch `json:"kubeadmConfigPatches,omitempty" description:"The patches applied to the kubeadm configuration generated by kk in order."`
+	Kubelet                       KubeletConfig        `json:"kubelet,omitempty" description:"The kubelet configuration of all the nodes, it can be overridden by kubeletPools and by the hosts."`
+	KubeletPools                  []KubeletPool        `json:"kubeletPools,omitempty" description:"The kubelet configurations of the groups of hosts, they are applied in order."`
+	Audit                         Audit                `json:"audit,omitempty" description:"The audit policy and backend of kube-apiserver."`
+	Encryption                    Encryption           `json:"encryption,omitempty" description:"The encryption at rest of the resources stored in etcd."`
+	Authentication                Authentication       `json:"authentication,omitempty" description:"The OIDC and webhook authentication and the webhook authorization of kube-apiserver."`
+	Admission                     Admission            `json:"admission,omitempty" description:"The admission plugins of kube-apiserver and their configurations."`
+	CloudProvider                 string               `json:"cloudProvider,omitempty" description:"The cloud provider of kubelet, kube-apiserver and kube-controller-manager, the nodes are initialized by the cloudControllerManager if it is external." enum:"external"`
+	CloudControllerManager        Addon                `json:"cloudControllerManager,omitempty" description:"The cloud-controller-manager installed from a helm chart or yaml files before the network plugin, it is required if the cloudProvider is external."`
+	KubeProxy                     KubeProxy            `json:"kubeProxy,omitempty" description:"The ipvs, iptables and conntrack configuration of kube-proxy, it is patched into the kube-proxy ConfigMap of existing clusters."`
+}
+
+// KubeProxy defines the KubeProxyConfiguration of kube-proxy.
+type KubeProxy struct {
+	IPVS      KubeProxyIPVS      `json:"ipvs,omitempty" description:"The options of the ipvs proxy mode, they are only accepted if the proxyMode is ipvs."`
+	IPTables  KubeProxyIPTables  `json:"iptables,omitempty" description:"The options of the iptables rules."`
+	Conntrack KubeProxyConntrack `json:"conntrack,omitempty" description:"The conntrack limits and timeouts set by kube-proxy on the nodes."`
+}
+
+// KubeProxyIPVS defines the options of the ipvs proxy mode.
+type KubeProxyIPVS struct {
+	Scheduler     string   `json:"scheduler,omitempty" description:"The ipvs scheduler. [Default: rr]" enum:"rr,wrr,lc,wlc,lblc,lblcr,sh,dh,sed,nq"`
+	StrictARP     bool     `json:"strictARP,omitempty" description:"Whether to enable the strict ARP, which is required by MetalLB. [Default: false]"`
+	ExcludeCIDRs  []string `json:"excludeCIDRs,omitempty" description:"The CIDRs whose ipvs rules are not cleaned up by kube-proxy."`
+	SyncPeriod    string   `json:"syncPeriod,omitempty" description:"The maximum interval of refreshing the ipvs rules. [Default: 30s]"`
+	MinSyncPeriod string   `json:"minSyncPeriod,omitempty" description:"The minimum interval of refreshing the ipvs rules. [Default: 0s]"`
+	TCPTimeout    string   `json:"tcpTimeout,omitempty" description:"The timeout of idle ipvs TCP sessions."`
+	TCPFinTimeout string   `json:"tcpFinTimeout,omitempty" description:"The timeout of ipvs TCP sessions after receiving a FIN."`
+	UDPTimeout    string   `json:"udpTimeout,omitempty" description:"The timeout of ipvs UDP packets."`
+}
+
+// KubeProxyIPTables defines the options of the iptables rules.
+type KubeProxyIPTables struct {
+	MasqueradeBit int    `json:"masqueradeBit,omitempty" description:"The bit of the fwmark space marking the packets to SNAT, it is in the range [1, 31] as 0 is taken as unset. [Default: 14]"`
+	SyncPeriod    string `json:"syncPeriod,omitempty" description:"The maximum interval of refreshing the iptables rules. [Default: 30s]"`
+	MinSyncPeriod string `json:"minSyncPeriod,omitempty" description:"The minimum interval of refreshing the iptables rules. [Default: 0s]"`
+}
+
+// KubeProxyConntrack defines the conntrack limits and timeouts.
+type KubeProxyConntrack struct {
+	MaxPerCore            int    `json:"maxPerCore,omitempty" description:"The maximum number of NAT connections to track per CPU core. [Default: 32768]"`
+	Min                   int    `json:"min,omitempty" description:"The minimum number of conntrack entries to allocate. [Default: 131072]"`
+	TCPEstablishedTimeout string `json:"tcpEstablishedTimeout,omitempty" description:"The idle timeout of established TCP connections. [Default: 24h0m0s]"`
+	TCPCloseWaitTimeout   string `json:"tcpCloseWaitTimeout,omitempty" description:"The timeout of TCP connections in the CLOSE_WAIT state. [Default: 1h0m0s]"`
+}
+
+// Admission defines the admission plugins of kube-apiserver and their configurations.
+type Admission struct {
+	EnablePlugins     []string                `json:"enablePlugins,omitempty" description:"The admission plugins enabled in addition to NodeRestriction and the ones enabled by default."`
+	DisablePlugins    []string                `json:"disablePlugins,omitempty" description:"The admission plugins disabled."`
+	PluginConfigs     []AdmissionPluginConfig `json:"pluginConfigs,omitempty" description:"The configurations of the admission plugins, the plugins configured are enabled as well."`
+	PodSecurity       PodSecurity             `json:"podSecurity,omitempty" description:"The defaults and exemptions of the PodSecurity admission plugin. Kubernetes v1.23+ is required."`
+	PodSecurityPolicy bool                    `json:"podSecurityPolicy,omitempty" description:"Whether to enable the PodSecurityPolicy plugin with a privileged policy for the nodes and kube-system and a baseline policy for the others. Kubernetes v1.24 or older is required. [Default: false]"`
+}
+
+// AdmissionPluginConfig defines the configuration of an admission plugin.
+type AdmissionPluginConfig struct {
+	Name              string `json:"name,omitempty" description:"The name of the admission plugin, such as EventRateLimit and PodNodeSelector."`
+	Configuration     string `json:"configuration,omitempty" description:"The configuration of the plugin in YAML."`
+	ConfigurationFile string `json:"configurationFile,omitempty" description:"The path of the configuration file of the plugin on the machine running kk."`
+}
+
+// PodSecurity defines the cluster-wide defaults and exemptions of the PodSecurity admission plugin.
+type PodSecurity struct {
+	Enforce              string   `json:"enforce,omitempty" description:"The level enforced on the namespaces without the label. [Default: privileged]" enum:"privileged,baseline,restricted"`
+	EnforceVersion       string   `json:"enforceVersion,omitempty" description:"The version of the enforced level. [Default: latest]"`
+	Audit                string   `json:"audit,omitempty" description:"The level audited on the namespaces without the label. [Default: privileged]" enum:"privileged,baseline,restricted"`
+	AuditVersion         string   `json:"auditVersion,omitempty" description:"The version of the audited level. [Default: latest]"`
+	Warn                 string   `json:"warn,omitempty" description:"The level warned on the namespaces without the label. [Default: privileged]" enum:"privileged,baseline,restricted"`
+	WarnVersion          string   `json:"warnVersion,omitempty" description:"The version of the warned level. [Default: latest]"`
+	ExemptNamespaces     []string `json:"exemptNamespaces,omitempty" description:"The namespaces exempted from the checks. [Default: [kube-system]]"`
+	ExemptUsernames      []string `json:"exemptUsernames,omitempty" description:"The users exempted from the checks."`
+	ExemptRuntimeClasses []string `json:"exemptRuntimeClasses,omitempty" description:"The runtime classes exempted from the checks."`
+}
+
+// Authentication defines the OIDC and webhook authentication and the webhook authorization of kube-apiserver.
+type Authentication struct {
+	OIDC                 OIDC                 `json:"oidc,omitempty" description:"The OpenID Connect token authenticator, it is enabled if issuerURL is given."`
+	Webhook              TokenWebhook         `json:"webhook,omitempty" description:"The webhook token authenticator, it is enabled if config or configFile is given."`
+	AuthorizationWebhook AuthorizationWebhook `json:"authorizationWebhook,omitempty" description:"The webhook authorizer consulted after Node and RBAC, it is enabled if config or configFile is given."`
+}
+
+// OIDC defines the OpenID Connect token authenticator.
+type OIDC struct {
+	IssuerURL      string            `json:"issuerURL,omitempty" description:"The URL of the OpenID issuer, only the https scheme is accepted."`
+	ClientID       string            `json:"clientID,omitempty" description:"The client ID for the OpenID Connect client, it is required if issuerURL is given."`
+	UsernameClaim  string            `json:"usernameClaim,omitempty" description:"The JWT claim used as the user name. [Default of kube-apiserver: sub]"`
+	UsernamePrefix string            `json:"usernamePrefix,omitempty" description:"The prefix prepended to the user names, '-' disables prefixing."`
+	GroupsClaim    string            `json:"groupsClaim,omitempty" description:"The JWT claim used as the groups of the user."`
+	GroupsPrefix   string            `json:"groupsPrefix,omitempty" description:"The prefix prepended to the groups."`
+	RequiredClaims map[string]string `json:"requiredClaims,omitempty" description:"The claims and values required in the ID token."`
+	SigningAlgs    []string          `json:"signingAlgs,omitempty" description:"The accepted signing algorithms. [Default of kube-apiserver: [RS256]]"`
+	CA             string            `json:"ca,omitempty" description:"The PEM encoded CA of the issuer. The host's root CAs are used if neither ca nor caFile is given."`
+	CAFile         string            `json:"caFile,omitempty" description:"The path of the CA of the issuer on the machine running kk."`
+}
+
+// TokenWebhook defines the webhook token authenticator.
+type TokenWebhook struct {
+	Config     string `json:"config,omitempty" description:"The kubeconfig of the webhook in YAML."`
+	ConfigFile string `json:"configFile,omitempty" description:"The path of the kubeconfig of the webhook on the machine running kk."`
+	CacheTTL   string `json:"cacheTTL,omitempty" description:"The duration to cache the responses. [Default of kube-apiserver: 2m]"`
+	Version    string `json:"version,omitempty" description:"The API version of TokenReview sent to the webhook. [Default of kube-apiserver: v1beta1]" enum:"v1,v1beta1"`
+}
+
+// AuthorizationWebhook defines the webhook authorizer.
+type AuthorizationWebhook struct {
+	Config               string `json:"config,omitempty" description:"The kubeconfig of the webhook in YAML."`
+	ConfigFile           string `json:"configFile,omitempty" description:"The path of the kubeconfig of the webhook on the machine running kk."`
+	CacheAuthorizedTTL   string `json:"cacheAuthorizedTTL,omitempty" description:"The duration to cache the authorized responses. [Default of kube-apiserver: 5m]"`
+	CacheUnauthorizedTTL string `json:"cacheUnauthorizedTTL,omitempty" description:"The duration to cache the unauthorized responses. [Default of kube-apiserver: 30s]"`
+	Version              string `json:"version,omitempty" description:"The API version of SubjectAccessReview sent to the webhook. [Default of kube-apiserver: v1beta1]" enum:"v1,v1beta1"`
+}
+
+// Encryption defines the encryption at rest of the resources stored in etcd.
+type Encryption struct {
+	Enabled   bool     `json:"enabled,omitempty" description:"Whether to encrypt the resources in etcd, the key is generated by kk and can be rotated by 'kk rotate encryption-key'. [Default: false]"`
+	Provider  string   `json:"provider,omitempty" description:"The encryption provider used for new keys. [Default: aescbc]" enum:"aescbc,secretbox"`
+	Resources []string `json:"resources,omitempty" description:"The resources to encrypt. [Default: [secrets]]"`
+}
+
+// Audit defines the audit policy and backend of kube-apiserver.
+type Audit struct {
+	Enabled           bool   `json:"enabled,omitempty" description:"Whether to enable the audit logging of kube-apiserver. [Default: false]"`
+	Policy            string `json:"policy,omitempty" description:"The audit policy in YAML. A default policy is used if neither policy nor policyFile is given."`
+	PolicyFile        string `json:"policyFile,omitempty" description:"The path of the audit policy file on the machine running kk."`
+	Backend           string `json:"backend,omitempty" description:"The audit backend. [Default: log]" enum:"log,webhook"`
+	LogPath           string `json:"logPath,omitempty" description:"The path of the audit log file. [Default: /var/log/apiserver/audit.log]"`
+	LogMaxAge         int    `json:"logMaxAge,omitempty" description:"The maximum number of days to retain the rotated audit log files. [Default: 30]"`
+	LogMaxBackup      int    `json:"logMaxBackup,omitempty" description:"The maximum number of rotated audit log files to retain. [Default: 10]"`
+	LogMaxSize        int    `json:"logMaxSize,omitempty" description:"The maximum size in megabytes of the audit log file before it is rotated. [Default: 100]"`
+	WebhookConfig     string `json:"webhookConfig,omitempty" description:"The kubeconfig of the audit webhook backend in YAML."`
+	WebhookConfigFile string `json:"webhookConfigFile,omitempty" description:"The path of the kubeconfig of the audit webhook backend on the machine running kk."`
+}
+
+// KubeletConfig defines the resource reservations and eviction thresholds of kubelet.
+// The maps are merged key by key over the less specific configurations, the other fields take effect if they are set.
+type KubeletConfig struct {
+	KubeReserved                     map[string]string `json:"kubeReserved,omitempty" description:"The resources reserved for kubernetes components. [Default: {cpu: 200m, memory: 250Mi}]"`
+	SystemReserved                   map[string]string `json:"systemReserved,omitempty" description:"The resources reserved for system daemons. [Default: {cpu: 200m, memory: 250Mi}]"`
+	EvictionHard                     map[string]string `json:"evictionHard,omitempty" description:"The hard eviction thresholds. [Default: {memory.available: 5%}]"`
+	EvictionSoft                     map[string]string `json:"evictionSoft,omitempty" description:"The soft eviction thresholds. [Default: {memory.available: 10%}]"`
+	EvictionSoftGracePeriod          map[string]string `json:"evictionSoftGracePeriod,omitempty" description:"The grace periods of the soft eviction thresholds. [Default: {memory.available: 2m}]"`
+	EvictionMaxPodGracePeriod        int               `json:"evictionMaxPodGracePeriod,omitempty" description:"The maximum grace period in seconds to terminate pods on soft eviction. [Default: 120]"`
+	EvictionPressureTransitionPeriod string            `json:"evictionPressureTransitionPeriod,omitempty" description:"The period kubelet waits before transitioning out of an eviction pressure condition. [Default: 30s]"`
+	ContainerLogMaxSize              string            `json:"containerLogMaxSize,omitempty" description:"The maximum size of a container log file before it is rotated, it is not used with docker. [Default: 5Mi]"`
+	ContainerLogMaxFiles             int               `json:"containerLogMaxFiles,omitempty" description:"The maximum number of log files of a container, it is not used with docker. [Default: 3]"`
+	ServerTLSBootstrap               bool              `json:"serverTLSBootstrap,omitempty" description:"Request the serving certificate of kubelet from the cluster and approve it by a CSR approver. Kubernetes v1.19+ is required."`
+}
+
+// KubeletPool defines the kubelet configuration of the hosts given by name or selected by labels.
+type KubeletPool struct {
+	Name          string            `json:"name,omitempty" description:"The name of the pool."`
+	Hosts         []string          `json:"hosts,omitempty" description:"The names of the hosts in the pool."`
+	Labels        map[string]string `json:"labels,omitempty" description:"The hosts having all the labels are in the pool."`
+	KubeletConfig `json:",inline"`
+}
+
+// KubeadmConfigPatch defines a patch applied to the documents of the given kind in the kubeadm configuration.
+type KubeadmConfigPatch struct {
+	Kind  string `json:"kind" description:"The kind of the kubeadm configuration documents to patch." enum:"ClusterConfiguration,InitConfiguration,JoinConfiguration,KubeletConfiguration,KubeProxyConfiguration"`
+	Type  string `json:"type,omitempty" description:"The type of the patch, a strategic merge patch merging the lists of named objects by name and appending to the lists of scalars, a JSON merge patch (RFC 7386) replacing lists as a whole, or a JSON patch (RFC 6902). [Default: strategic]" enum:"strategic,merge,json"`
+	Patch string `json:"patch" description:"The patch in YAML or JSON."`
+}
+
+// HostPathMount defines a volume mounted from the host into a static pod of the control plane.
+type HostPathMount struct {
+	Name      string `json:"name" description:"The name of the volume."`
+	HostPath  string `json:"hostPath" description:"The path on the host."`
+	MountPath string `json:"mountPath" description:"The path inside the pod."`
+	ReadOnly  bool   `json:"readOnly,omitempty" description:"Whether the volume is mounted read-only."`
+	PathType  string `json:"pathType,omitempty" description:"The type of the host path." enum:",DirectoryOrCreate,Directory,FileOrCreate,File,Socket,CharDevice,BlockDevice"`
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+type NetworkConfig struct {
+	Plugin          string     `json:"plugin,omitempty" description:"The network plugin. [Default: calico]" enum:"calico,flannel,cilium,kubeovn,none"`
+	KubePodsCIDR    string     `json:"kubePodsCIDR,omitempty" description:"The CIDR of pods, an IPv4 and an IPv6 CIDR separated by a comma for a dual-stack cluster. [Default: 10.233.64.0/18]"`
+	KubeServiceCIDR string     `json:"kubeServiceCIDR,omitempty" description:"The CIDR of services, an IPv4 and an IPv6 CIDR separated by a comma for a dual-stack cluster. [Default: 10.233.0.0/18]"`
+	Calico          CalicoCfg  `json:"calico,omitempty" description:"The configuration of calico."`
+	Flannel         FlannelCfg `json:"flannel,omitempty" description:"The configuration of flannel."`
+	Kubeovn         KubeovnCfg `json:"kubeovn,omitempty" description:"The configuration of kube-ovn."`
+}
+
+type CalicoCfg struct {
+	IPIPMode  string `json:"ipipMode,omitempty" description:"The IPIP mode of the IPv4 pool, vxlanMode should be Never if it is not Never. [Default: Always]" enum:"Always,CrossSubnet,Never"`
+	VXLANMode string `json:"vxlanMode,omitempty" description:"The VXLAN mode of the IPv4 pool, ipipMode should be Never if it is not Never. [Default: Never]" enum:"Always,CrossSubnet,Never"`
+	VethMTU   int    `json:"vethMTU,omitempty" description:"The MTU of the veth interfaces. [Default: 1440]"`
+}
+
+type FlannelCfg struct {
+	BackendMode string `json:"backendMode,omitempty" description:"The backend of flannel. [Default: vxlan]" enum:"vxlan,host-gw"`
+}
+
+type KubeovnCfg struct {
+	JoinCIDR              string `json:"joinCIDR,omitempty"`
+	NetworkType           string `json:"networkType,omitempty" description:"The network type of kube-ovn. [Default: geneve]" enum:"geneve,vlan"`
+	Label                 string `json:"label,omitempty"`
+	Iface                 string `json:"iface,omitempty"`
+	VlanInterfaceName     string `json:"vlanInterfaceName,omitempty"`
+	VlanID                string `json:"vlanID,omitempty"`
+	DpdkMode              bool   `json:"dpdkMode,omitempty"`
+	EnableSSL             bool   `json:"enableSSL,omitempty"`
+	EnableMirror          bool   `json:"enableMirror,omitempty"`
+	HwOffload             bool   `json:"hwOffload,omitempty"`
+	DpdkVersion           string `json:"dpdkVersion,omitempty"`
+	PingerExternalAddress string `json:"pingerExternalAddress,omitempty"`
+	PingerExternalDomain  string `json:"pingerExternalDomain,omitempty"`
+}
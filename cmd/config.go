@@ -31,7 +31,7 @@ var configCmd = &cobra.Command{
 		} else {
 			ksVersion = ""
 		}
-		err := config.GenerateClusterObj(opt.Kubernetes, ksVersion, opt.Name, opt.Kubeconfig, opt.ClusterCfgPath, opt.Kubesphere, opt.FromCluster)
+		err := config.GenerateClusterObj(opt.Kubernetes, ksVersion, opt.Name, opt.Kubeconfig, opt.ClusterCfgPath, opt.HostCredentials, opt.Kubesphere, opt.FromCluster)
 		if err != nil {
 			return err
 		}
@@ -48,4 +48,5 @@ func init() {
 	configCmd.Flags().BoolVarP(&opt.Kubesphere, "with-kubesphere", "", false, "Deploy a specific version of kubesphere (default v3.0.0)")
 	configCmd.Flags().BoolVarP(&opt.FromCluster, "from-cluster", "", false, "Create a configuration based on existing cluster")
 	configCmd.Flags().StringVarP(&opt.Kubeconfig, "kubeconfig", "", "", "Specify a kubeconfig file")
+	configCmd.Flags().StringVarP(&opt.HostCredentials, "host-credentials", "", "", "Specify a file with the ssh information of hosts, used with --from-cluster")
 }
@@ -11,12 +11,12 @@ var deleteClusterCmd = &cobra.Command{
 	Short: "Delete a cluster",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		logger := util.InitLogger(opt.Verbose)
-		return delete.ResetCluster(opt.ClusterCfgFile, logger, opt.Verbose)
+		return delete.ResetCluster(clusterCfgSources(), logger, opt.Verbose)
 	},
 }
 
 func init() {
 	deleteCmd.AddCommand(deleteClusterCmd)
 
-	deleteClusterCmd.Flags().StringVarP(&opt.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
+	addClusterCfgFlags(deleteClusterCmd)
 }
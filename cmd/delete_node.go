@@ -4,19 +4,21 @@ import (
 	"github.com/kubesphere/kubekey/pkg/delete"
 	"github.com/kubesphere/kubekey/pkg/util"
 	"github.com/spf13/cobra"
-	"strings"
 )
 
 var deleteNodeCmd = &cobra.Command{
-	Use:   "node",
-	Short: "delete a node",
-	Run: func(cmd *cobra.Command, args []string) {
+	Use:   "node NAME...",
+	Short: "delete nodes, masters and etcd members are supported",
+	RunE: func(cmd *cobra.Command, args []string) error {
 		logger := util.InitLogger(opt.Verbose)
-		delete.ResetNode(opt.ClusterCfgFile, logger, opt.Verbose, strings.Join(args, ""))
+		return delete.DeleteNodes(clusterCfgSources(), logger, opt.Verbose, args, opt.DrainTimeout, opt.UpdateConfig)
 	},
 }
 
 func init() {
 	deleteCmd.AddCommand(deleteNodeCmd)
-	deleteNodeCmd.Flags().StringVarP(&opt.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
+
+	addClusterCfgFlags(deleteNodeCmd)
+	deleteNodeCmd.Flags().DurationVar(&opt.DrainTimeout, "drain-timeout", 0, "The length of time to wait before giving up draining a node, zero means infinite")
+	deleteNodeCmd.Flags().BoolVar(&opt.UpdateConfig, "update-config", false, "Remove the deleted nodes from the configuration files")
 }
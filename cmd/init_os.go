@@ -27,13 +27,13 @@ var osCmd = &cobra.Command{
 	Short: "Init operating system",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		logger := util.InitLogger(opt.Verbose)
-		return bootstrap.Init(opt.ClusterCfgFile, opt.SourcesDir, opt.AddImagesRepo, logger)
+		return bootstrap.Init(clusterCfgSources(), opt.SourcesDir, opt.AddImagesRepo, logger)
 	},
 }
 
 func init() {
 	initCmd.AddCommand(osCmd)
-	osCmd.Flags().StringVarP(&opt.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
+	addClusterCfgFlags(osCmd)
 	osCmd.Flags().StringVarP(&opt.SourcesDir, "sources", "s", "", "Path to the dependencies' dir")
 	osCmd.Flags().BoolVarP(&opt.AddImagesRepo, "add-images-repo", "", false, "Create a local images registry")
 }
@@ -11,12 +11,12 @@ var listClusterCertsCmd = &cobra.Command{
 	Short: "Check certificates expiration for a Kubernetes cluster",
 	Run: func(cmd *cobra.Command, args []string) {
 		logger := util.InitLogger(opt.Verbose)
-		cert.ListCluster(opt.ClusterCfgFile, logger, opt.Verbose)
+		cert.ListCluster(clusterCfgSources(), logger, opt.Verbose)
 	},
 }
 
 func init() {
 	certsCmd.AddCommand(listClusterCertsCmd)
 
-	listClusterCertsCmd.Flags().StringVarP(&opt.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
+	addClusterCfgFlags(listClusterCertsCmd)
 }
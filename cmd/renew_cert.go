@@ -11,12 +11,12 @@ var renewClusterCertsCmd = &cobra.Command{
 	Short: "renew a cluster certs",
 	Run: func(cmd *cobra.Command, args []string) {
 		logger := util.InitLogger(opt.Verbose)
-		cert.RenewClusterCerts(opt.ClusterCfgFile, logger, opt.Verbose)
+		cert.RenewClusterCerts(clusterCfgSources(), logger, opt.Verbose)
 	},
 }
 
 func init() {
 	certsCmd.AddCommand(renewClusterCertsCmd)
 
-	renewClusterCertsCmd.Flags().StringVarP(&opt.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
+	addClusterCfgFlags(renewClusterCertsCmd)
 }
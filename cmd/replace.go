@@ -0,0 +1,13 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+// replaceCmd represents the replace command
+var replaceCmd = &cobra.Command{
+	Use:   "replace",
+	Short: "Replace nodes of kubernetes cluster",
+}
+
+func init() {
+	rootCmd.AddCommand(replaceCmd)
+}
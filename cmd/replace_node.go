@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"github.com/kubesphere/kubekey/pkg/replace"
+	"github.com/kubesphere/kubekey/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// replaceNodeCmd represents the replace node command
+var replaceNodeCmd = &cobra.Command{
+	Use:     "node NAME --with HOST",
+	Short:   "Replace a node, which may be unreachable, with a new host of the same roles",
+	Example: `  kk replace node node3 --with '{name: node9, address: 172.16.0.9, internalAddress: 172.16.0.9}' -f config-sample.yaml`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger := util.InitLogger(opt.Verbose)
+		return replace.ReplaceNode(clusterCfgSources(), logger, opt.Verbose, args[0], opt.ReplaceWith, opt.DrainTimeout, opt.SkipCheck, opt.SkipPullImages, opt.UpdateConfig)
+	},
+}
+
+func init() {
+	replaceCmd.AddCommand(replaceNodeCmd)
+	addClusterCfgFlags(replaceNodeCmd)
+	replaceNodeCmd.Flags().StringVar(&opt.ReplaceWith, "with", "", "The new host in the same form as the hosts of the configuration, the fields not given are inherited from the old node")
+	replaceNodeCmd.Flags().DurationVar(&opt.DrainTimeout, "drain-timeout", 0, "The length of time to wait before giving up draining the old node, zero means infinite")
+	replaceNodeCmd.Flags().BoolVar(&opt.UpdateConfig, "update-config", false, "Replace the old node with the new host in the configuration files")
+	replaceNodeCmd.Flags().BoolVarP(&opt.SkipCheck, "yes", "y", false, "Skip pre-check of the installation")
+	replaceNodeCmd.Flags().BoolVarP(&opt.SkipPullImages, "skip-pull-images", "", false, "Skip pre pull images")
+	_ = replaceNodeCmd.MarkFlagRequired("with")
+}
@@ -17,28 +17,40 @@ package cmd
 
 import (
 	"fmt"
+	"github.com/kubesphere/kubekey/pkg/catalog"
+	"github.com/kubesphere/kubekey/pkg/config"
 	"github.com/spf13/cobra"
 	"os"
 	"os/exec"
+	"time"
 )
 
 type Options struct {
-	Verbose        bool
-	Addons         string
-	Name           string
-	ClusterCfgPath string
-	Kubeconfig     string
-	FromCluster    bool
-	ClusterCfgFile string
-	Kubernetes     string
-	Kubesphere     bool
-	SkipCheck      bool
-	SkipPullImages bool
-	KsVersion      string
-	Registry       string
-	SourcesDir     string
-	AddImagesRepo  bool
-	InCluster      bool
+	Verbose         bool
+	Addons          string
+	Name            string
+	ClusterCfgPath  string
+	Kubeconfig      string
+	FromCluster     bool
+	HostCredentials string
+	ClusterCfgFiles []string
+	SetValues       []string
+	VarFiles        []string
+	Vars            []string
+	Strict          bool
+	Kubernetes      string
+	Kubesphere      bool
+	SkipCheck       bool
+	SkipPullImages  bool
+	KsVersion       string
+	Registry        string
+	SourcesDir      string
+	AddImagesRepo   bool
+	InCluster       bool
+	DrainTimeout    time.Duration
+	UpdateConfig    bool
+	ReplaceWith     string
+	Catalog         string
 }
 
 var (
@@ -53,6 +65,12 @@ var rootCmd = &cobra.Command{
 1. Install Kubernetes only
 2. Install Kubernetes and KubeSphere together in one command
 3. Install Kubernetes first, then deploy KubeSphere on it using https://github.com/kubesphere/ks-installer`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if opt.Catalog != "" {
+			return catalog.Load(opt.Catalog)
+		}
+		return nil
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -72,7 +90,27 @@ func init() {
 	// will be global for your application.
 	rootCmd.PersistentFlags().BoolVar(&opt.InCluster, "in-cluster", false, "Running inside the cluster")
 	rootCmd.PersistentFlags().BoolVar(&opt.Verbose, "debug", true, "Print detailed information")
+	rootCmd.PersistentFlags().StringVar(&opt.Catalog, "catalog", "", "Path to a catalog of the supported versions, binaries and images, merged over the built-in catalog")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 }
+
+// addClusterCfgFlags adds the flags used to specify the cluster configuration to the given command.
+func addClusterCfgFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVarP(&opt.ClusterCfgFiles, "filename", "f", []string{}, "Path to a configuration file, can be specified multiple times to merge files in order")
+	cmd.Flags().StringArrayVar(&opt.SetValues, "set", []string{}, "Override values of the configuration, e.g. --set spec.kubernetes.version=v1.18.8")
+	cmd.Flags().StringArrayVar(&opt.Vars, "var", []string{}, "Set a variable used to render the configuration, e.g. --var clusterName=prod")
+	cmd.Flags().StringSliceVar(&opt.VarFiles, "var-file", []string{}, "Path to a YAML file defining the variables used to render the configuration")
+	cmd.Flags().BoolVar(&opt.Strict, "strict", false, "Reject the configuration containing unknown fields instead of ignoring them")
+}
+
+func clusterCfgSources() *config.ClusterCfgSources {
+	return &config.ClusterCfgSources{
+		Files:     opt.ClusterCfgFiles,
+		SetValues: opt.SetValues,
+		VarFiles:  opt.VarFiles,
+		Vars:      opt.Vars,
+		Strict:    opt.Strict,
+	}
+}
@@ -0,0 +1,12 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var rotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Rotate the keys of a cluster",
+}
+
+func init() {
+	rootCmd.AddCommand(rotateCmd)
+}
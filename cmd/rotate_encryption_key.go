@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"github.com/kubesphere/kubekey/pkg/rotate"
+	"github.com/kubesphere/kubekey/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var rotateEncryptionKeyCmd = &cobra.Command{
+	Use:   "encryption-key",
+	Short: "Rotate the key used to encrypt the secrets at rest",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger := util.InitLogger(opt.Verbose)
+		return rotate.RotateEncryptionKey(clusterCfgSources(), logger, opt.Verbose)
+	},
+}
+
+func init() {
+	rotateCmd.AddCommand(rotateEncryptionKeyCmd)
+
+	addClusterCfgFlags(rotateEncryptionKeyCmd)
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/kubesphere/kubekey/pkg/config"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// schemaCmd represents the config schema command
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of the cluster configuration file",
+	Long: `Print the JSON Schema of the cluster configuration file, it can be used by editors to validate and complete the file.
+For example, with the yaml language server: # yaml-language-server: $schema=./cluster-schema.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		schema, err := json.MarshalIndent(config.ClusterSchema(), "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "Failed to generate the schema of the cluster configuration")
+		}
+		fmt.Println(string(schema))
+		return nil
+	},
+}
+
+func init() {
+	cfgCmd.AddCommand(schemaCmd)
+}
@@ -34,7 +34,7 @@ import (
 	"path/filepath"
 )
 
-func AddNodes(clusterCfgFile, k8sVersion, ksVersion string, logger *log.Logger, ksEnabled, verbose, skipCheck, skipPullImages, inCluster bool) error {
+func AddNodes(clusterCfgSources *config.ClusterCfgSources, k8sVersion, ksVersion string, logger *log.Logger, ksEnabled, verbose, skipCheck, skipPullImages, inCluster bool) error {
 	currentDir, err := filepath.Abs(filepath.Dir(os.Args[0]))
 	if err != nil {
 		return errors.Wrap(err, "Failed to get current dir")
@@ -43,7 +43,7 @@ func AddNodes(clusterCfgFile, k8sVersion, ksVersion string, logger *log.Logger,
 		return errors.Wrap(err, "Failed to create work dir")
 	}
 
-	cfg, objName, err := config.ParseClusterCfg(clusterCfgFile, k8sVersion, ksVersion, ksEnabled, logger)
+	cfg, objName, err := config.ParseClusterCfg(clusterCfgSources, k8sVersion, ksVersion, ksEnabled, logger)
 	if err != nil {
 		return errors.Wrap(err, "Failed to download cluster config")
 	}
@@ -69,13 +69,26 @@ func ExecTasks(mgr *manager.Manager) error {
 		{Task: preinstall.PrePullImages, ErrMsg: "Failed to pre-pull images"},
 		{Task: etcd.GenerateEtcdCerts, ErrMsg: "Failed to generate etcd certs"},
 		{Task: etcd.SyncEtcdCertsToMaster, ErrMsg: "Failed to sync etcd certs"},
+		{Task: etcd.CheckExternalEtcd, ErrMsg: "Failed to check the external etcd"},
 		{Task: etcd.GenerateEtcdService, ErrMsg: "Failed to create etcd service"},
 		{Task: etcd.SetupEtcdCluster, ErrMsg: "Failed to start etcd cluster"},
 		{Task: etcd.RefreshEtcdConfig, ErrMsg: "Failed to refresh etcd configuration"},
 		{Task: etcd.BackupEtcd, ErrMsg: "Failed to backup etcd data"},
 		{Task: kubernetes.GetClusterStatus, ErrMsg: "Failed to get cluster status"},
 		{Task: kubernetes.InstallKubeBinaries, ErrMsg: "Failed to install kube binaries"},
+		{Task: kubernetes.SyncAuditPolicy, ErrMsg: "Failed to sync audit policy"},
+		{Task: kubernetes.SyncEncryptionConfig, ErrMsg: "Failed to sync encryption config"},
+		{Task: kubernetes.SyncAuthenticationConfig, ErrMsg: "Failed to sync authentication config"},
+		{Task: kubernetes.SyncAdmissionConfig, ErrMsg: "Failed to sync admission config"},
+		{Task: kubernetes.ConfigureLoadBalancer, ErrMsg: "Failed to configure load balancer"},
+		{Task: kubernetes.DeployPodSecurityPolicy, ErrMsg: "Failed to deploy pod security policies"},
+		{Task: kubernetes.UploadKubeadmConfig, ErrMsg: "Failed to upload kubeadm config"},
 		{Task: kubernetes.JoinNodesToCluster, ErrMsg: "Failed to join node"},
+		{Task: kubernetes.DeployInternalLoadbalancer, ErrMsg: "Failed to deploy the internal load balancer"},
+		{Task: etcd.BackupStackedEtcd, ErrMsg: "Failed to backup etcd data"},
+		{Task: kubernetes.ConfigureKubelet, ErrMsg: "Failed to configure kubelet"},
+		{Task: kubernetes.ConfigureKubeProxy, ErrMsg: "Failed to configure kube-proxy"},
+		{Task: kubernetes.DeployKubeletCsrApprover, ErrMsg: "Failed to deploy kubelet-csr-approver"},
 	}
 
 	for _, step := range addNodeTasks {
@@ -0,0 +1,38 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/kubesphere/kubekey/pkg/util/manager"
+)
+
+// DeployCloudControllerManager is used to install the cloud-controller-manager given by users if the cloud provider is external.
+// kubelet registers the nodes with the node.cloudprovider.kubernetes.io/uninitialized taint, which is removed once the node is initialized
+// by the cloud-controller-manager, so it is installed before the network plugin and the other workloads.
+func DeployCloudControllerManager(mgr *manager.Manager) error {
+	if !mgr.Cluster.ExternalCloudProvider() {
+		return nil
+	}
+
+	ccm := mgr.Cluster.Kubernetes.CloudControllerManager
+	mgr.Logger.Infof("Installing cloud-controller-manager: %s", ccm.Name)
+
+	return installAddon(mgr, &ccm, filepath.Join(mgr.WorkDir, fmt.Sprintf("config-%s", mgr.ObjName)))
+}
@@ -28,7 +28,7 @@ import (
 	"path/filepath"
 )
 
-func Init(clusterCfgFile, sourcesDir string, addImagesRepo bool, logger *log.Logger) error {
+func Init(clusterCfgSources *config.ClusterCfgSources, sourcesDir string, addImagesRepo bool, logger *log.Logger) error {
 	currentDir, err := filepath.Abs(filepath.Dir(os.Args[0]))
 	if err != nil {
 		return errors.Wrap(err, "Failed to get current dir")
@@ -37,7 +37,7 @@ func Init(clusterCfgFile, sourcesDir string, addImagesRepo bool, logger *log.Log
 		return errors.Wrap(err, "Failed to create work dir")
 	}
 
-	cfg, objName, err := config.ParseClusterCfg(clusterCfgFile, "", "", false, logger)
+	cfg, objName, err := config.ParseClusterCfg(clusterCfgSources, "", "", false, logger)
 	if err != nil {
 		return errors.Wrap(err, "Failed to download cluster config")
 	}
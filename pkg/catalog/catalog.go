@@ -0,0 +1,347 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"regexp"
+	"sync"
+	"text/template"
+
+	"github.com/kubesphere/kubekey/pkg/util"
+	"github.com/pkg/errors"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/yaml"
+)
+
+// Catalog describes the versions of kubernetes supported by kk, the binaries downloaded for them,
+// the default tags of the images installed with them and the rules of upgrading.
+type Catalog struct {
+	// Defaults are the versions of the binaries and the tags of the images of all the versions of kubernetes, they are overridden by the releases.
+	Defaults   Components          `json:"defaults"`
+	Kubernetes []KubernetesRelease `json:"kubernetes"`
+	// Binaries are the download URLs and the checksums of the binaries, keyed by the name of the binary.
+	Binaries   map[string]Binary   `json:"binaries"`
+	Upgrade    UpgradeRules        `json:"upgrade"`
+	KubeSphere []KubeSphereRelease `json:"kubesphere"`
+}
+
+// Components defines the versions of the binaries and the tags of the images installed with kubernetes.
+type Components struct {
+	Binaries map[string]string `json:"binaries,omitempty"`
+	Images   map[string]string `json:"images,omitempty"`
+}
+
+// KubernetesRelease defines a supported version of kubernetes and the components which differ from the defaults.
+type KubernetesRelease struct {
+	Version    string `json:"version"`
+	Components `json:",inline"`
+}
+
+// Binary defines where a binary is downloaded from and its checksums.
+// The URLs are templates of the version and the arch of the binary, e.g. https://example.com/{{ .Version }}/{{ .Arch }}/kubeadm.
+type Binary struct {
+	URL string `json:"url"`
+	// ZoneURLs are the URLs used instead of the URL when the KKZONE environment variable is set to the key.
+	ZoneURLs map[string]string `json:"zoneUrls,omitempty"`
+	// SHA256 are the checksums of the binary keyed by the arch and the version.
+	SHA256 map[string]map[string]string `json:"sha256,omitempty"`
+}
+
+// UpgradeRules defines the versions of kubernetes which the clusters without KubeSphere can be upgraded to.
+type UpgradeRules struct {
+	Kubernetes []string `json:"kubernetes"`
+}
+
+// KubeSphereRelease defines the minor versions of kubernetes supported by a version of KubeSphere, and the versions of KubeSphere it is upgraded from.
+type KubeSphereRelease struct {
+	Version     string   `json:"version"`
+	Kubernetes  []string `json:"kubernetes"`
+	UpgradeFrom []string `json:"upgradeFrom,omitempty"`
+}
+
+var (
+	current     *Catalog
+	currentOnce sync.Once
+
+	sha256Regexp = regexp.MustCompile(`^[0-9a-f]{64}$`)
+)
+
+// Get returns the catalog in use, which is the default catalog unless another one is loaded.
+func Get() *Catalog {
+	currentOnce.Do(func() {
+		if current != nil {
+			return
+		}
+		c, err := parse([]byte(defaultCatalog))
+		if err != nil {
+			panic(fmt.Sprintf("Invalid default catalog: %v", err))
+		}
+		current = c
+	})
+	return current
+}
+
+// Load reads the catalog file and merges it over the default catalog, the releases and the binaries given replace or are added to the default ones.
+// The merged catalog is used by the following calls to Get.
+func Load(path string) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to read the catalog %s", path))
+	}
+	src, err := parse(content)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Invalid catalog %s", path))
+	}
+	c := *Get()
+	c.merge(src)
+	if err := c.validate(); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Invalid catalog %s", path))
+	}
+	current = &c
+	return nil
+}
+
+func parse(content []byte) (*Catalog, error) {
+	c := &Catalog{}
+	if err := yaml.UnmarshalStrict(content, c); err != nil {
+		return nil, errors.Wrap(errors.WithStack(err), "Failed to parse the catalog")
+	}
+	return c, c.validate()
+}
+
+func (c *Catalog) validate() error {
+	for _, release := range c.Kubernetes {
+		if _, err := versionutil.ParseSemantic(release.Version); err != nil {
+			return errors.New(fmt.Sprintf("Invalid version of kubernetes: %s", release.Version))
+		}
+	}
+	for name, binary := range c.Binaries {
+		for _, u := range append([]string{binary.URL}, mapValues(binary.ZoneURLs)...) {
+			if _, err := template.New(name).Parse(u); err != nil {
+				return errors.New(fmt.Sprintf("Invalid URL of %s: %s", name, u))
+			}
+		}
+		for arch, checksums := range binary.SHA256 {
+			for version, checksum := range checksums {
+				if !sha256Regexp.MatchString(checksum) {
+					return errors.New(fmt.Sprintf("Invalid SHA256 of %s %s %s: %s", name, version, arch, checksum))
+				}
+			}
+		}
+	}
+	for _, release := range c.KubeSphere {
+		if release.Version == "" {
+			return errors.New("The version of KubeSphere is required")
+		}
+	}
+	return nil
+}
+
+// merge merges src over the catalog, the maps are copied so the catalog merged is not modified.
+func (c *Catalog) merge(src *Catalog) {
+	c.Defaults = c.Defaults.merge(&src.Defaults)
+
+	releases := append([]KubernetesRelease{}, c.Kubernetes...)
+	for _, release := range src.Kubernetes {
+		replaced := false
+		for i := range releases {
+			if releases[i].Version == release.Version {
+				releases[i] = release
+				replaced = true
+			}
+		}
+		if !replaced {
+			releases = append(releases, release)
+		}
+	}
+	c.Kubernetes = releases
+
+	binaries := map[string]Binary{}
+	for name, binary := range c.Binaries {
+		binaries[name] = binary
+	}
+	for name, binary := range src.Binaries {
+		binaries[name] = binaries[name].merge(&binary)
+	}
+	c.Binaries = binaries
+
+	if len(src.Upgrade.Kubernetes) > 0 {
+		c.Upgrade = src.Upgrade
+	}
+
+	kubeSphereReleases := append([]KubeSphereRelease{}, c.KubeSphere...)
+	for _, release := range src.KubeSphere {
+		replaced := false
+		for i := range kubeSphereReleases {
+			if kubeSphereReleases[i].Version == release.Version {
+				kubeSphereReleases[i] = release
+				replaced = true
+			}
+		}
+		if !replaced {
+			kubeSphereReleases = append(kubeSphereReleases, release)
+		}
+	}
+	c.KubeSphere = kubeSphereReleases
+}
+
+func (c Components) merge(src *Components) Components {
+	return Components{Binaries: mergeMaps(c.Binaries, src.Binaries), Images: mergeMaps(c.Images, src.Images)}
+}
+
+func (b Binary) merge(src *Binary) Binary {
+	if src.URL != "" {
+		b.URL = src.URL
+	}
+	b.ZoneURLs = mergeMaps(b.ZoneURLs, src.ZoneURLs)
+	checksums := map[string]map[string]string{}
+	for arch, m := range b.SHA256 {
+		checksums[arch] = m
+	}
+	for arch, m := range src.SHA256 {
+		checksums[arch] = mergeMaps(checksums[arch], m)
+	}
+	b.SHA256 = checksums
+	return b
+}
+
+// KubernetesVersions returns the supported versions of kubernetes in the order of the catalog.
+func (c *Catalog) KubernetesVersions() []string {
+	versions := make([]string, 0, len(c.Kubernetes))
+	for _, release := range c.Kubernetes {
+		versions = append(versions, release.Version)
+	}
+	return versions
+}
+
+// LatestPatchVersion returns the latest supported version of kubernetes with the given major and minor version.
+func (c *Catalog) LatestPatchVersion(major, minor uint) (string, bool) {
+	var latest *versionutil.Version
+	latestStr := ""
+	for _, release := range c.Kubernetes {
+		v, err := versionutil.ParseSemantic(release.Version)
+		if err != nil || v.Major() != major || v.Minor() != minor {
+			continue
+		}
+		if latest == nil || latest.LessThan(v) {
+			latest, latestStr = v, release.Version
+		}
+	}
+	return latestStr, latest != nil
+}
+
+// ComponentsOf returns the versions of the binaries and the tags of the images installed with the given version of kubernetes.
+// The defaults are returned for the versions not in the catalog.
+func (c *Catalog) ComponentsOf(version string) Components {
+	for i := range c.Kubernetes {
+		if c.Kubernetes[i].Version == version {
+			return c.Defaults.merge(&c.Kubernetes[i].Components)
+		}
+	}
+	return c.Defaults.merge(&Components{})
+}
+
+// BinaryVersion returns the version of the binary installed with the given version of kubernetes.
+func (c *Catalog) BinaryVersion(kubeVersion, name string) string {
+	return c.ComponentsOf(kubeVersion).Binaries[name]
+}
+
+// ImageTag returns the tag of the image installed with the given version of kubernetes.
+func (c *Catalog) ImageTag(kubeVersion, name string) string {
+	return c.ComponentsOf(kubeVersion).Images[name]
+}
+
+// BinaryURL returns the download URL of the binary, the URL of the zone is used if there is one.
+func (c *Catalog) BinaryURL(name, arch, version, zone string) (string, error) {
+	binary, ok := c.Binaries[name]
+	if !ok {
+		return "", errors.New(fmt.Sprintf("The binary %s is not in the catalog", name))
+	}
+	u := binary.URL
+	if zoneURL, ok := binary.ZoneURLs[zone]; ok && zone != "" {
+		u = zoneURL
+	}
+	rendered, err := util.Render(template.Must(template.New(name).Parse(u)), util.Data{"Version": version, "Arch": arch})
+	if err != nil {
+		return "", errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to render the URL of %s", name))
+	}
+	if _, err := url.Parse(rendered); err != nil {
+		return "", errors.New(fmt.Sprintf("Invalid URL of %s: %s", name, rendered))
+	}
+	return rendered, nil
+}
+
+// SHA256 returns the checksum of the binary, or an empty string if it is not in the catalog.
+func (c *Catalog) SHA256(name, arch, version string) string {
+	return c.Binaries[name].SHA256[arch][version]
+}
+
+// UpgradeSupported returns true if the clusters without KubeSphere can be upgraded to the minor version of kubernetes, e.g. v1.18.
+func (c *Catalog) UpgradeSupported(kubeMinorVersion string) bool {
+	return contains(c.Upgrade.Kubernetes, kubeMinorVersion)
+}
+
+// KubeSphereRelease returns the release of the given version of KubeSphere.
+func (c *Catalog) KubeSphereRelease(version string) (*KubeSphereRelease, bool) {
+	for i := range c.KubeSphere {
+		if c.KubeSphere[i].Version == version {
+			return &c.KubeSphere[i], true
+		}
+	}
+	return nil, false
+}
+
+// SupportsKubernetes returns true if the release of KubeSphere runs on the minor version of kubernetes, e.g. v1.18.
+func (r *KubeSphereRelease) SupportsKubernetes(kubeMinorVersion string) bool {
+	return contains(r.Kubernetes, kubeMinorVersion)
+}
+
+// UpgradableFrom returns true if the release of KubeSphere can be upgraded from the given version.
+func (r *KubeSphereRelease) UpgradableFrom(version string) bool {
+	return contains(r.UpgradeFrom, version)
+}
+
+func mergeMaps(dst, src map[string]string) map[string]string {
+	merged := map[string]string{}
+	for k, v := range dst {
+		merged[k] = v
+	}
+	for k, v := range src {
+		merged[k] = v
+	}
+	return merged
+}
+
+func mapValues(m map[string]string) []string {
+	values := make([]string, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+	return values
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -11,9 +11,11 @@ import (
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	clientcmdlatest "k8s.io/client-go/tools/clientcmd/api/latest"
 	certutil "k8s.io/client-go/util/cert"
+	"net"
 	"os"
 	"strings"
 	"text/tabwriter"
@@ -36,8 +38,9 @@ type CaCertificate struct {
 }
 
 const (
-	kubernetesDir = "/etc/kubernetes/"
-	certDir       = kubernetesDir + "pki/"
+	kubernetesDir  = "/etc/kubernetes/"
+	certDir        = kubernetesDir + "pki/"
+	kubeletCertDir = "/var/lib/kubelet/pki/"
 )
 
 var (
@@ -55,38 +58,61 @@ var (
 		"controller-manager.conf",
 		"scheduler.conf",
 	}
+	// the certs of the stacked etcd of kubeadm
+	etcdCertificateList = []string{
+		"etcd/server.crt",
+		"etcd/peer.crt",
+		"etcd/healthcheck-client.crt",
+		"apiserver-etcd-client.crt",
+	}
+	etcdCaCertificateList = []string{
+		"etcd/ca.crt",
+	}
+	// the serving certs of kubelet, the one issued by the cluster is preferred
+	kubeletCertificateList = []string{
+		"kubelet-server-current.pem",
+		"kubelet.crt",
+	}
 	certificates    = []*Certificate{}
 	caCertificates  = []*CaCertificate{}
 	kubeConfigValue = map[string]string{}
 )
 
+// kubeadmList are the certificates renewed by kubeadm.
 var kubeadmList = []string{
-	"cd /etc/kubernetes",
-	"/usr/local/bin/kubeadm alpha certs renew apiserver",
-	"/usr/local/bin/kubeadm alpha certs renew apiserver-kubelet-client",
-	"/usr/local/bin/kubeadm alpha certs renew front-proxy-client",
-	"/usr/local/bin/kubeadm alpha certs renew admin.conf",
-	"/usr/local/bin/kubeadm alpha certs renew controller-manager.conf",
-	"/usr/local/bin/kubeadm alpha certs renew scheduler.conf",
+	"apiserver",
+	"apiserver-kubelet-client",
+	"front-proxy-client",
+	"admin.conf",
+	"controller-manager.conf",
+	"scheduler.conf",
 }
 
+// etcdKubeadmList are the certificates of the etcd managed by kubeadm.
+var etcdKubeadmList = []string{
+	"etcd-server",
+	"etcd-peer",
+	"etcd-healthcheck-client",
+	"apiserver-etcd-client",
+}
+
+// restartList are the static pods of the control plane restarted to load the certificates renewed.
 var restartList = []string{
-	"docker ps -af name=k8s_kube-apiserver* -q | xargs --no-run-if-empty docker rm -f",
-	"docker ps -af name=k8s_kube-scheduler* -q | xargs --no-run-if-empty docker rm -f",
-	"docker ps -af name=k8s_kube-controller-manager* -q | xargs --no-run-if-empty docker rm -f",
-	"systemctl restart kubelet",
+	kubernetes.KubeApiserver,
+	kubernetes.KubeControllerManager,
+	kubernetes.KubeScheduler,
 }
 
-func ListCluster(clusterCfgFile string, logger *log.Logger, verbose bool) error {
-	cfg, objName, err := config.ParseClusterCfg(clusterCfgFile, "", "", false, logger)
+func ListCluster(clusterCfgSources *config.ClusterCfgSources, logger *log.Logger, verbose bool) error {
+	cfg, objName, err := config.ParseClusterCfg(clusterCfgSources, "", "", false, logger)
 	if err != nil {
 		return errors.Wrap(err, "Failed to download cluster config")
 	}
 	return Execute(executor.NewExecutor(&cfg.Spec, objName, logger, "", verbose, false, true, false, false, nil))
 
 }
-func RenewClusterCerts(clusterCfgFile string, logger *log.Logger, verbose bool) error {
-	cfg, objName, err := config.ParseClusterCfg(clusterCfgFile, "", "", false, logger)
+func RenewClusterCerts(clusterCfgSources *config.ClusterCfgSources, logger *log.Logger, verbose bool) error {
+	cfg, objName, err := config.ParseClusterCfg(clusterCfgSources, "", "", false, logger)
 	if err != nil {
 		return errors.Wrap(err, "Failed to download cluster config")
 	}
@@ -141,12 +167,21 @@ func ListClusterCerts(m *manager.Manager) error {
 	if err := m.RunTaskOnMasterNodes(listClusterCerts, true); err != nil {
 		return err
 	}
+	if err := m.RunTaskOnK8sNodes(listKubeletCerts, false); err != nil {
+		return err
+	}
 	printResult(certificates, caCertificates)
 	return nil
 }
 
 func listClusterCerts(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) error {
-	for _, certFileName := range certificateList {
+	certList, caCertList := certificateList, caCertificateList
+	if mgr.Cluster.Etcd.Type == kubekeyapiv1alpha1.EtcdTypeKubeadm {
+		certList = append(append([]string{}, certList...), etcdCertificateList...)
+		caCertList = append(append([]string{}, caCertList...), etcdCaCertificateList...)
+	}
+
+	for _, certFileName := range certList {
 		certPath := fmt.Sprintf("%s%s", certDir, certFileName)
 		certContext, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"cat %s\"", certPath), 1, false)
 		if err != nil {
@@ -183,7 +218,7 @@ func listClusterCerts(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) er
 		}
 	}
 
-	for _, caCertFileName := range caCertificateList {
+	for _, caCertFileName := range caCertList {
 		certPath := fmt.Sprintf("%s%s", certDir, caCertFileName)
 		caCertContext, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"cat %s\"", certPath), 1, false)
 		if err != nil {
@@ -199,6 +234,28 @@ func listClusterCerts(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) er
 	return nil
 }
 
+// listKubeletCerts lists the serving certificate of kubelet, which is issued by the cluster CA if serverTLSBootstrap is enabled and approved,
+// or self-signed by kubelet otherwise.
+func listKubeletCerts(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) error {
+	for _, certFileName := range kubeletCertificateList {
+		certPath := fmt.Sprintf("%s%s", kubeletCertDir, certFileName)
+		if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"test -f %s\"", certPath), 0, false); err != nil {
+			continue
+		}
+		certContext, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"cat %s\"", certPath), 1, false)
+		if err != nil {
+			return errors.Wrap(err, "Failed to get kubelet certs")
+		}
+		cert, err := getCertInfo(certContext, certFileName, node.Name)
+		if err != nil {
+			return err
+		}
+		certificates = append(certificates, cert)
+		return nil
+	}
+	return nil
+}
+
 func printResult(certificates []*Certificate, caCertificates []*CaCertificate) {
 	w := tabwriter.NewWriter(os.Stdout, 10, 4, 3, ' ', 0)
 	fmt.Fprintln(w, "CERTIFICATE\tEXPIRES\tRESIDUAL TIME\tCERTIFICATE AUTHORITY\tNODE")
@@ -241,8 +298,12 @@ func getCertInfo(certContext, certFileName, nodeName string) (*Certificate, erro
 		authorityName = "ca"
 	case "apiserver-kubelet-client.crt":
 		authorityName = "ca"
+	case "kubelet-server-current.pem":
+		authorityName = "ca"
 	case "front-proxy-client.crt":
 		authorityName = "front-proxy-ca"
+	case "etcd/server.crt", "etcd/peer.crt", "etcd/healthcheck-client.crt", "apiserver-etcd-client.crt":
+		authorityName = "etcd-ca"
 	default:
 		authorityName = ""
 	}
@@ -294,14 +355,34 @@ func SyncKubeConfig(m *manager.Manager) error {
 	return m.RunTaskOnWorkerNodes(syncKubeConfig, true)
 }
 
-func renewClusterCerts(mgr *manager.Manager, _ *kubekeyapiv1alpha1.HostCfg) error {
-	_, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s\"", strings.Join(kubeadmList, " && ")), 5, false)
+func renewClusterCerts(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) error {
+	if err := cleanApiserverCertSANs(mgr, node); err != nil {
+		return err
+	}
+
+	certsCmd := kubeadmCertsCmd(mgr.Cluster.Kubernetes.Version)
+	renewList := []string{"cd /etc/kubernetes"}
+	for _, name := range kubeadmList {
+		renewList = append(renewList, fmt.Sprintf("%s renew %s", certsCmd, name))
+	}
+	if mgr.Cluster.Etcd.Type == kubekeyapiv1alpha1.EtcdTypeKubeadm {
+		for _, name := range etcdKubeadmList {
+			renewList = append(renewList, fmt.Sprintf("%s renew %s", certsCmd, name))
+		}
+	}
+	_, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s\"", strings.Join(renewList, " && ")), 5, false)
 	if err != nil {
-		return errors.Wrap(err, "Failed to kubeadm alpha certs renew...")
+		return errors.Wrap(err, fmt.Sprintf("Failed to %s renew...", strings.TrimPrefix(certsCmd, "/usr/local/bin/")))
 	}
-	_, err1 := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s\"", strings.Join(restartList, " && ")), 5, false)
-	if err1 != nil {
-		return errors.Wrap(err1, "Failed to restart kube-apiserver or kube-schedule or kube-controller-manager")
+	staticPods := restartList
+	if mgr.Cluster.Etcd.Type == kubekeyapiv1alpha1.EtcdTypeKubeadm {
+		staticPods = append([]string{kubernetes.Etcd}, staticPods...)
+	}
+	if err := kubernetes.RestartStaticPods(mgr, node, staticPods...); err != nil {
+		return err
+	}
+	if _, err := mgr.Runner.ExecuteCmd("sudo -E /bin/sh -c \"systemctl restart kubelet\"", 5, false); err != nil {
+		return errors.Wrap(err, "Failed to restart kubelet")
 	}
 
 	if err := kubernetes.GetKubeConfig(mgr); err != nil {
@@ -317,6 +398,62 @@ func renewClusterCerts(mgr *manager.Manager, _ *kubekeyapiv1alpha1.HostCfg) erro
 	}
 	return nil
 }
+
+// kubeadmCertsCmd returns the kubeadm command to manage certificates, which is moved out of "kubeadm alpha" since v1.20.
+func kubeadmCertsCmd(version string) string {
+	if versionutil.MustParseSemantic(version).AtLeast(versionutil.MustParseSemantic("v1.20.0")) {
+		return "/usr/local/bin/kubeadm certs"
+	}
+	return "/usr/local/bin/kubeadm alpha certs"
+}
+
+// cleanApiserverCertSANs regenerates the certificate of kube-apiserver when it holds SANs which are not in the cluster any more,
+// such as the names and addresses of the deleted masters. "kubeadm certs renew" keeps the SANs of the existing certificate.
+func cleanApiserverCertSANs(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) error {
+	certContext, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"cat %sapiserver.crt\"", certDir), 1, false)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get the certificate of kube-apiserver")
+	}
+	certs, err := certutil.ParseCertsPEM([]byte(certContext))
+	if err != nil {
+		return errors.Wrap(err, "Failed to parse the certificate of kube-apiserver")
+	}
+
+	certSANs := mgr.Cluster.GenerateCertSANs()
+	sans := map[string]bool{}
+	for _, san := range certSANs {
+		if ip := net.ParseIP(san); ip != nil {
+			san = ip.String()
+		}
+		sans[san] = true
+	}
+	var staleSANs []string
+	for _, name := range certs[0].DNSNames {
+		if !sans[name] {
+			staleSANs = append(staleSANs, name)
+		}
+	}
+	for _, ip := range certs[0].IPAddresses {
+		if !sans[ip.String()] {
+			staleSANs = append(staleSANs, ip.String())
+		}
+	}
+	if len(staleSANs) == 0 {
+		return nil
+	}
+
+	mgr.Logger.Infof("Removing SANs %s from the certificate of kube-apiserver on %s\n", strings.Join(staleSANs, ","), node.Name)
+	generateCertCmd := fmt.Sprintf("/usr/local/bin/kubeadm init phase certs apiserver --kubernetes-version=%s --apiserver-advertise-address=%s --apiserver-cert-extra-sans=%s --control-plane-endpoint=%s:%d --service-cidr=%s --service-dns-domain=%s",
+		mgr.Cluster.Kubernetes.Version, node.InternalAddress, strings.Join(certSANs, ","), mgr.Cluster.ControlPlaneEndpoint.Domain, mgr.Cluster.ControlPlaneEndpoint.Port, mgr.Cluster.Network.KubeServiceCIDR, mgr.Cluster.Kubernetes.ClusterName)
+	// the old certificate is restored if the new one can not be generated
+	regenerateCmd := fmt.Sprintf("cd %s && mv -f apiserver.crt apiserver.crt.old && mv -f apiserver.key apiserver.key.old && "+
+		"(%s || (mv -f apiserver.crt.old apiserver.crt && mv -f apiserver.key.old apiserver.key && exit 1)) && rm -f apiserver.crt.old apiserver.key.old", certDir, generateCertCmd)
+	if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s\"", regenerateCmd), 1, false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "Failed to regenerate the certificate of kube-apiserver")
+	}
+	return nil
+}
+
 func syncKubeConfig(mgr *manager.Manager, _ *kubekeyapiv1alpha1.HostCfg) error {
 	createConfigDirCmd := "mkdir -p /root/.kube && mkdir -p $HOME/.kube"
 	chownKubeConfig := "chown $(id -u):$(id -g) -R $HOME/.kube"
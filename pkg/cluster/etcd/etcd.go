@@ -21,10 +21,12 @@ import (
 	"fmt"
 	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
 	kubekeycontroller "github.com/kubesphere/kubekey/controllers/kubekey"
+	"github.com/kubesphere/kubekey/pkg/catalog"
 	"github.com/kubesphere/kubekey/pkg/cluster/etcd/tmpl"
 	"github.com/kubesphere/kubekey/pkg/cluster/preinstall"
 	"github.com/kubesphere/kubekey/pkg/util/manager"
 	"github.com/pkg/errors"
+	"io/ioutil"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"os"
 	"path/filepath"
@@ -49,6 +51,10 @@ func GenerateEtcdCerts(mgr *manager.Manager) error {
 		}
 	}
 
+	if mgr.Cluster.Etcd.Type != kubekeyapiv1alpha1.EtcdTypeKubeKey {
+		return nil
+	}
+
 	mgr.Logger.Infoln("Generating etcd certs")
 
 	return mgr.RunTaskOnEtcdNodes(generateCerts, true)
@@ -128,6 +134,16 @@ func generateCertsFiles(mgr *manager.Manager) []string {
 }
 
 func SyncEtcdCertsToMaster(mgr *manager.Manager) error {
+	switch mgr.Cluster.Etcd.Type {
+	case kubekeyapiv1alpha1.EtcdTypeKubeKey:
+	case kubekeyapiv1alpha1.EtcdTypeExternal:
+		if err := loadExternalEtcdCerts(mgr); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+
 	mgr.Logger.Infoln("Synchronizing etcd certs")
 
 	return mgr.RunTaskOnMasterNodes(syncEtcdCertsToMaster, true)
@@ -147,7 +163,56 @@ func syncEtcdCertsToMaster(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCf
 	return nil
 }
 
+// loadExternalEtcdCerts reads the CA and the client certificate of the external etcd from the local files,
+// they are written to the masters as the files returned by EtcdClientCerts.
+func loadExternalEtcdCerts(mgr *manager.Manager) error {
+	caFile, certFile, keyFile := mgr.Cluster.EtcdClientCerts("")
+	localFiles := map[string]string{
+		caFile:   mgr.Cluster.Etcd.External.CaFile,
+		certFile: mgr.Cluster.Etcd.External.CertFile,
+		keyFile:  mgr.Cluster.Etcd.External.KeyFile,
+	}
+	for remoteFile, localFile := range localFiles {
+		content, err := ioutil.ReadFile(localFile)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Failed to read the certificate of the external etcd: %s", localFile))
+		}
+		certsContent[filepath.Base(remoteFile)] = base64.StdEncoding.EncodeToString(content)
+	}
+	return nil
+}
+
+// CheckExternalEtcd is used to check that every master is able to access all the members of the external etcd before initializing the cluster.
+func CheckExternalEtcd(mgr *manager.Manager) error {
+	if mgr.Cluster.Etcd.Type != kubekeyapiv1alpha1.EtcdTypeExternal {
+		return nil
+	}
+
+	mgr.Logger.Infoln("Checking the external etcd")
+
+	return mgr.RunTaskOnMasterNodes(checkExternalEtcd, true)
+}
+
+func checkExternalEtcd(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) error {
+	caFile, certFile, keyFile := mgr.Cluster.EtcdClientCerts(node.Name)
+	for _, endpoint := range mgr.Cluster.Etcd.External.Endpoints {
+		checkHealthCmd := fmt.Sprintf("curl -sS --connect-timeout 5 --cacert %s --cert %s --key %s %s/health", caFile, certFile, keyFile, strings.TrimSuffix(endpoint, "/"))
+		output, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s\"", checkHealthCmd), 2, false)
+		if err != nil {
+			return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to connect to the external etcd %s from %s", endpoint, node.Name))
+		}
+		if !strings.Contains(strings.Replace(output, " ", "", -1), `"health":"true"`) {
+			return errors.New(fmt.Sprintf("The external etcd %s is unhealthy: %s", endpoint, output))
+		}
+	}
+	return nil
+}
+
 func GenerateEtcdService(mgr *manager.Manager) error {
+	if mgr.Cluster.Etcd.Type != kubekeyapiv1alpha1.EtcdTypeKubeKey {
+		return nil
+	}
+
 	mgr.Logger.Infoln("Creating etcd service")
 
 	return mgr.RunTaskOnEtcdNodes(generateEtcdService, true)
@@ -209,7 +274,7 @@ func installEtcdBinaries(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg)
 		if err1 != nil {
 			return errors.Wrap(err1, "Failed to get current dir")
 		}
-		etcdFile := fmt.Sprintf("etcd-%s-linux-%s", kubekeyapiv1alpha1.DefaultEtcdVersion, node.Arch)
+		etcdFile := fmt.Sprintf("etcd-%s-linux-%s", catalog.Get().BinaryVersion(mgr.Cluster.Kubernetes.Version, "etcd"), node.Arch)
 		filesDir := fmt.Sprintf("%s/%s/%s/%s", currentDir, kubekeyapiv1alpha1.DefaultPreDir, mgr.Cluster.Kubernetes.Version, node.Arch)
 		if err := mgr.Runner.ScpFile(fmt.Sprintf("%s/%s.tar.gz", filesDir, etcdFile), fmt.Sprintf("%s/%s.tar.gz", "/tmp/kubekey", etcdFile)); err != nil {
 			return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to sync etcd tar.gz"))
@@ -230,6 +295,10 @@ func installEtcdBinaries(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg)
 }
 
 func SetupEtcdCluster(mgr *manager.Manager) error {
+	if mgr.Cluster.Etcd.Type != kubekeyapiv1alpha1.EtcdTypeKubeKey {
+		return nil
+	}
+
 	mgr.Logger.Infoln("Starting etcd cluster")
 
 	return mgr.RunTaskOnEtcdNodes(setupEtcdCluster, false)
@@ -241,7 +310,7 @@ func setupEtcdCluster(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) er
 	output, _ := mgr.Runner.ExecuteCmd("sudo -E /bin/sh -c \"[ -f /etc/etcd.env ] && echo 'Configuration file already exists' || echo 'Configuration file will be created'\"", 0, true)
 	if strings.TrimSpace(output) == "Configuration file already exists" {
 		outTmp, _ := mgr.Runner.ExecuteCmd("sudo cat /etc/etcd.env | awk 'NR==1{print $6}'", 0, true)
-		if outTmp != kubekeyapiv1alpha1.DefaultEtcdVersion {
+		if outTmp != catalog.Get().BinaryVersion(mgr.Cluster.Kubernetes.Version, "etcd") {
 			if err := refreshConfig(mgr, node, mgr.Runner.Index, localPeerAddresses, "existing"); err != nil {
 				return err
 			}
@@ -307,16 +376,23 @@ func setupEtcdCluster(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) er
 }
 
 func RefreshEtcdConfig(mgr *manager.Manager) error {
+	if mgr.Cluster.Etcd.Type != kubekeyapiv1alpha1.EtcdTypeKubeKey {
+		return nil
+	}
+
 	mgr.Logger.Infoln("Refreshing etcd configuration")
 
 	return mgr.RunTaskOnEtcdNodes(refreshEtcdConfig, true)
 }
 
 func BackupEtcd(mgr *manager.Manager) error {
-	mgr.Logger.Infoln("Backup etcd data regularly")
+	// the stacked etcd of kubeadm is backed up by BackupStackedEtcd after the masters are set up
+	if mgr.Cluster.Etcd.Type == kubekeyapiv1alpha1.EtcdTypeKubeKey {
+		mgr.Logger.Infoln("Backup etcd data regularly")
 
-	if err := mgr.RunTaskOnEtcdNodes(backupEtcd, true); err != nil {
-		return err
+		if err := mgr.RunTaskOnEtcdNodes(backupEtcd, true); err != nil {
+			return err
+		}
 	}
 
 	if mgr.InCluster {
@@ -328,6 +404,24 @@ func BackupEtcd(mgr *manager.Manager) error {
 	return nil
 }
 
+// BackupStackedEtcd is used to back up the stacked etcd of kubeadm regularly, etcdctl is installed on the masters for it.
+func BackupStackedEtcd(mgr *manager.Manager) error {
+	if mgr.Cluster.Etcd.Type != kubekeyapiv1alpha1.EtcdTypeKubeadm {
+		return nil
+	}
+
+	mgr.Logger.Infoln("Backup etcd data regularly")
+
+	return mgr.RunTaskOnEtcdNodes(backupStackedEtcd, true)
+}
+
+func backupStackedEtcd(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) error {
+	if err := installEtcdBinaries(mgr, node); err != nil {
+		return err
+	}
+	return backupEtcd(mgr, node)
+}
+
 // Create etcd backup scripts.
 func backupEtcd(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) error {
 	_, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"mkdir -p %s\"", mgr.Cluster.Kubernetes.EtcdBackupScriptDir), 0, false)
@@ -392,7 +486,7 @@ helthCheckLoop:
 }
 
 func refreshConfig(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg, index int, endpoints []string, state string) error {
-	etcdEnv, err := tmpl.GenerateEtcdEnv(node, index, endpoints, state)
+	etcdEnv, err := tmpl.GenerateEtcdEnv(mgr, node, index, endpoints, state)
 	if err != nil {
 		return err
 	}
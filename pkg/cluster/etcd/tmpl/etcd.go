@@ -22,6 +22,7 @@ import (
 	"text/template"
 
 	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+	"github.com/kubesphere/kubekey/pkg/catalog"
 	"github.com/kubesphere/kubekey/pkg/cluster/preinstall"
 	"github.com/kubesphere/kubekey/pkg/util"
 	"github.com/kubesphere/kubekey/pkg/util/manager"
@@ -142,13 +143,13 @@ func GenerateEtcdService(index int, etcdContainer bool) (string, error) {
 }
 
 // GenerateEtcdEnv is used to generate the etcd's env content.
-func GenerateEtcdEnv(node *kubekeyapiv1alpha1.HostCfg, index int, endpoints []string, state string) (string, error) {
+func GenerateEtcdEnv(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg, index int, endpoints []string, state string) (string, error) {
 	UnsupportedArch := false
 	if node.Arch != "amd64" {
 		UnsupportedArch = true
 	}
 	return util.Render(EtcdEnvTempl, util.Data{
-		"Tag":             kubekeyapiv1alpha1.DefaultEtcdVersion,
+		"Tag":             catalog.Get().BinaryVersion(mgr.Cluster.Kubernetes.Version, "etcd"),
 		"Name":            fmt.Sprintf("etcd%d", index+1),
 		"Ip":              node.InternalAddress,
 		"Hostname":        node.Name,
@@ -26,9 +26,9 @@ ETCDBACKUPPERIOD='{{ .EtcdBackupPeriod }}'
 ETCDBACKUPSCIPT='{{ .EtcdBackupScriptDir }}'
 ETCDBACKUPHOUR='{{ .EtcdBackupHour }}'
 
-ETCDCTL_CERT="/etc/ssl/etcd/ssl/admin-{{ .Hostname }}.pem"
-ETCDCTL_KEY="/etc/ssl/etcd/ssl/admin-{{ .Hostname }}-key.pem"
-ETCDCTL_CA_FILE="/etc/ssl/etcd/ssl/ca.pem"
+ETCDCTL_CERT="{{ .CertFile }}"
+ETCDCTL_KEY="{{ .KeyFile }}"
+ETCDCTL_CA_FILE="{{ .CaFile }}"
 
 [ ! -d $BACKUP_DIR ] && mkdir -p $BACKUP_DIR
 
@@ -83,8 +83,15 @@ func EtcdBackupScript(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) (s
 		}
 	}
 
+	caFile, certFile, keyFile := "/etc/ssl/etcd/ssl/ca.pem", fmt.Sprintf("/etc/ssl/etcd/ssl/admin-%s.pem", node.Name), fmt.Sprintf("/etc/ssl/etcd/ssl/admin-%s-key.pem", node.Name)
+	if mgr.Cluster.Etcd.Type == kubekeyapiv1alpha1.EtcdTypeKubeadm {
+		caFile, certFile, keyFile = "/etc/kubernetes/pki/etcd/ca.crt", "/etc/kubernetes/pki/etcd/healthcheck-client.crt", "/etc/kubernetes/pki/etcd/healthcheck-client.key"
+	}
+
 	return util.Render(EtcdBackupScriptTmpl, util.Data{
-		"Hostname":            node.Name,
+		"CaFile":              caFile,
+		"CertFile":            certFile,
+		"KeyFile":             keyFile,
 		"Etcdendpoint":        strings.Join(ips, ","),
 		"Backupdir":           mgr.Cluster.Kubernetes.EtcdBackupDir,
 		"KeepbackupNumber":    mgr.Cluster.Kubernetes.KeepBackupNumber,
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+	"github.com/kubesphere/kubekey/pkg/cluster/kubernetes/tmpl"
+	"github.com/kubesphere/kubekey/pkg/util/manager"
+	"github.com/pkg/errors"
+)
+
+const podSecurityPolicyManifest = "/etc/kubernetes/addons/pod-security-policy.yaml"
+
+// SyncAdmissionConfig is used to distribute the AdmissionConfiguration and the configs of the admission plugins to the masters.
+func SyncAdmissionConfig(mgr *manager.Manager) error {
+	if !mgr.Cluster.Kubernetes.Admission.Configured() {
+		return nil
+	}
+
+	mgr.Logger.Infoln("Syncing admission config")
+
+	return mgr.RunTaskOnMasterNodes(syncAdmissionConfig, true)
+}
+
+func syncAdmissionConfig(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) error {
+	admission := mgr.Cluster.Kubernetes.Admission
+	admissionConfig, err := tmpl.GenerateAdmissionConfig(mgr)
+	if err != nil {
+		return err
+	}
+	files := map[string]string{tmpl.AdmissionConfigPath: admissionConfig}
+
+	for _, pluginCfg := range admission.PluginConfigs {
+		content, err := yamlFileContent(pluginCfg.Configuration, pluginCfg.ConfigurationFile)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Failed to load the config of the admission plugin %s", pluginCfg.Name))
+		}
+		files[tmpl.AdmissionPluginConfigPath(pluginCfg.Name)] = content
+	}
+	if admission.PodSecurity.Enabled() {
+		podSecurityConfig, err := tmpl.GeneratePodSecurityConfig(mgr)
+		if err != nil {
+			return err
+		}
+		files[tmpl.AdmissionPluginConfigPath("PodSecurity")] = podSecurityConfig
+	}
+
+	for path, content := range files {
+		syncCmd := fmt.Sprintf("mkdir -p %s && echo %s | base64 -d > %s && chmod 600 %s", tmpl.AdmissionDir, base64.StdEncoding.EncodeToString([]byte(content)), path, path)
+		if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s\"", syncCmd), 1, false); err != nil {
+			return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to sync %s to %s", path, node.Name))
+		}
+	}
+	return nil
+}
+
+// DeployPodSecurityPolicy is used to install the pod security policies and their RBAC if the PodSecurityPolicy admission plugin is enabled.
+// The pods are rejected until a policy allows them, so it runs right after the control plane is initialized, and before the control plane is upgraded.
+func DeployPodSecurityPolicy(mgr *manager.Manager) error {
+	if !mgr.Cluster.Kubernetes.Admission.PodSecurityPolicy {
+		return nil
+	}
+
+	mgr.Logger.Infoln("Deploying pod security policies")
+
+	return mgr.RunTaskOnMasterNodes(deployPodSecurityPolicy, false)
+}
+
+func deployPodSecurityPolicy(mgr *manager.Manager, _ *kubekeyapiv1alpha1.HostCfg) error {
+	if mgr.Runner.Index != 0 {
+		return nil
+	}
+
+	syncCmd := fmt.Sprintf("mkdir -p /etc/kubernetes/addons && echo %s | base64 -d > %s", base64.StdEncoding.EncodeToString([]byte(tmpl.PodSecurityPolicyManifest)), podSecurityPolicyManifest)
+	if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s\"", syncCmd), 1, false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "Failed to generate pod security policies manifest")
+	}
+	if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"/usr/local/bin/kubectl apply -f %s\"", podSecurityPolicyManifest), 5, true); err != nil {
+		return errors.Wrap(errors.WithStack(err), "Failed to deploy pod security policies")
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+	"github.com/kubesphere/kubekey/pkg/cluster/kubernetes/tmpl"
+	"github.com/kubesphere/kubekey/pkg/util/manager"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// SyncAuditPolicy is used to distribute the audit policy and the webhook config of kube-apiserver to the masters.
+// kube-apiserver does not reload the files, so it is restarted on the masters one by one if they are changed on an existing cluster.
+func SyncAuditPolicy(mgr *manager.Manager) error {
+	if !mgr.Cluster.Kubernetes.Audit.Enabled {
+		return nil
+	}
+
+	mgr.Logger.Infoln("Syncing audit policy")
+
+	return mgr.RunTaskOnMasterNodes(syncAuditPolicy, false)
+}
+
+func syncAuditPolicy(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) error {
+	audit := mgr.Cluster.Kubernetes.Audit
+	policy, err := yamlFileContent(audit.Policy, audit.PolicyFile)
+	if err != nil {
+		return errors.Wrap(err, "Failed to load audit policy")
+	}
+	if policy == "" {
+		policy = tmpl.DefaultAuditPolicy
+	}
+	files := map[string]string{tmpl.AuditPolicyPath: policy}
+
+	if audit.Backend == "webhook" {
+		webhookConfig, err := yamlFileContent(audit.WebhookConfig, audit.WebhookConfigFile)
+		if err != nil {
+			return errors.Wrap(err, "Failed to load audit webhook config")
+		}
+		if webhookConfig == "" {
+			return errors.New("The webhookConfig or webhookConfigFile is required by the audit webhook backend")
+		}
+		files[tmpl.AuditWebhookConfigPath] = webhookConfig
+	}
+
+	changed := false
+	for path, content := range files {
+		output, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"if [ -f %s ]; then cat %s | base64 --wrap=0; fi\"", path, path), 1, false)
+		if err != nil {
+			return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to get %s on %s", path, node.Name))
+		}
+		if strings.TrimSpace(output) == base64.StdEncoding.EncodeToString([]byte(content)) {
+			continue
+		}
+		changed = true
+		syncCmd := fmt.Sprintf("mkdir -p %s && echo %s | base64 -d > %s && chmod 600 %s", tmpl.AuditDir, base64.StdEncoding.EncodeToString([]byte(content)), path, path)
+		if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s\"", syncCmd), 1, false); err != nil {
+			return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to sync %s to %s", path, node.Name))
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	// kube-apiserver is not running yet on the masters to be initialized or joined
+	manifest := fmt.Sprintf("%s/%s.yaml", tmpl.StaticPodDir, KubeApiserver)
+	output, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"if [ -f %s ]; then echo exist; fi\"", manifest), 1, false)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to find kube-apiserver on %s", node.Name))
+	}
+	if strings.TrimSpace(output) != "exist" {
+		return nil
+	}
+	return RestartStaticPods(mgr, node, KubeApiserver)
+}
+
+// yamlFileContent returns the inline content, or the content of the local file if it is not given. The content must be valid YAML.
+func yamlFileContent(content, path string) (string, error) {
+	if content == "" && path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		content = string(data)
+	}
+	if content != "" {
+		var values map[string]interface{}
+		if err := yaml.Unmarshal([]byte(content), &values); err != nil {
+			return "", errors.WithStack(err)
+		}
+	}
+	return content, nil
+}
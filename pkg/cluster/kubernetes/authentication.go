@@ -0,0 +1,95 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+
+	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+	"github.com/kubesphere/kubekey/pkg/cluster/kubernetes/tmpl"
+	"github.com/kubesphere/kubekey/pkg/util/manager"
+	"github.com/pkg/errors"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+// SyncAuthenticationConfig is used to distribute the OIDC CA and the kubeconfig files of the authentication and authorization webhooks to the masters.
+// It runs before the masters are initialized, joined or upgraded, so that the files referred by the flags of kube-apiserver always exist.
+func SyncAuthenticationConfig(mgr *manager.Manager) error {
+	if !mgr.Cluster.Kubernetes.Authentication.Enabled() {
+		return nil
+	}
+
+	mgr.Logger.Infoln("Syncing authentication config")
+
+	return mgr.RunTaskOnMasterNodes(syncAuthenticationConfig, true)
+}
+
+func syncAuthenticationConfig(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) error {
+	authn := mgr.Cluster.Kubernetes.Authentication
+	files := map[string]string{}
+
+	if authn.OIDC.Enabled() {
+		ca, err := oidcCAContent(authn.OIDC.CA, authn.OIDC.CAFile)
+		if err != nil {
+			return errors.Wrap(err, "Failed to load the CA of OIDC")
+		}
+		if ca != "" {
+			files[tmpl.OIDCCAPath] = ca
+		}
+	}
+	if authn.Webhook.Enabled() {
+		webhookConfig, err := yamlFileContent(authn.Webhook.Config, authn.Webhook.ConfigFile)
+		if err != nil {
+			return errors.Wrap(err, "Failed to load authentication webhook config")
+		}
+		files[tmpl.AuthnWebhookConfigPath] = webhookConfig
+	}
+	if authn.AuthorizationWebhook.Enabled() {
+		webhookConfig, err := yamlFileContent(authn.AuthorizationWebhook.Config, authn.AuthorizationWebhook.ConfigFile)
+		if err != nil {
+			return errors.Wrap(err, "Failed to load authorization webhook config")
+		}
+		files[tmpl.AuthorizationWebhookConfigPath] = webhookConfig
+	}
+
+	for path, content := range files {
+		syncCmd := fmt.Sprintf("mkdir -p %s && echo %s | base64 -d > %s && chmod 600 %s", tmpl.AuthnDir, base64.StdEncoding.EncodeToString([]byte(content)), path, path)
+		if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s\"", syncCmd), 1, false); err != nil {
+			return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to sync %s to %s", path, node.Name))
+		}
+	}
+	return nil
+}
+
+// oidcCAContent returns the inline CA, or the content of the local file if it is not given. The content must be PEM encoded certificates.
+func oidcCAContent(content, path string) (string, error) {
+	if content == "" && path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		content = string(data)
+	}
+	if content != "" {
+		if _, err := certutil.ParseCertsPEM([]byte(content)); err != nil {
+			return "", errors.WithStack(err)
+		}
+	}
+	return content, nil
+}
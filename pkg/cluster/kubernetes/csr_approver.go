@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+	"github.com/kubesphere/kubekey/pkg/cluster/kubernetes/tmpl"
+	"github.com/kubesphere/kubekey/pkg/util/manager"
+	"github.com/pkg/errors"
+)
+
+const kubeletCsrApproverManifest = "/etc/kubernetes/addons/kubelet-csr-approver.yaml"
+
+// DeployKubeletCsrApprover is used to deploy kubelet-csr-approver when any kubelet requests its serving certificate from the cluster.
+// The hosts are written into the manifest, so it is applied again whenever the nodes are changed.
+func DeployKubeletCsrApprover(mgr *manager.Manager) error {
+	if !mgr.Cluster.ServerTLSBootstrapEnabled() {
+		return nil
+	}
+
+	mgr.Logger.Infoln("Deploying kubelet-csr-approver")
+
+	return mgr.RunTaskOnMasterNodes(deployKubeletCsrApprover, false)
+}
+
+func deployKubeletCsrApprover(mgr *manager.Manager, _ *kubekeyapiv1alpha1.HostCfg) error {
+	if mgr.Runner.Index != 0 {
+		return nil
+	}
+
+	manifest, err := tmpl.GenerateKubeletCsrApproverManifest(mgr)
+	if err != nil {
+		return err
+	}
+	syncCmd := fmt.Sprintf("mkdir -p /etc/kubernetes/addons && echo %s | base64 -d > %s", base64.StdEncoding.EncodeToString([]byte(manifest)), kubeletCsrApproverManifest)
+	if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s\"", syncCmd), 1, false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "Failed to generate kubelet-csr-approver manifest")
+	}
+	if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"/usr/local/bin/kubectl apply -f %s\"", kubeletCsrApproverManifest), 5, true); err != nil {
+		return errors.Wrap(errors.WithStack(err), "Failed to deploy kubelet-csr-approver")
+	}
+	return nil
+}
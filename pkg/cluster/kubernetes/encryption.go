@@ -0,0 +1,294 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+	"github.com/kubesphere/kubekey/pkg/cluster/kubernetes/tmpl"
+	"github.com/kubesphere/kubekey/pkg/util/manager"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// encryptionConfiguration is the EncryptionConfiguration of kube-apiserver, only the providers supported by kk are kept.
+type encryptionConfiguration struct {
+	APIVersion string               `yaml:"apiVersion"`
+	Kind       string               `yaml:"kind"`
+	Resources  []encryptionResource `yaml:"resources"`
+}
+
+type encryptionResource struct {
+	Resources []string             `yaml:"resources"`
+	Providers []encryptionProvider `yaml:"providers"`
+}
+
+type encryptionProvider struct {
+	AESCBC    *encryptionKeys `yaml:"aescbc,omitempty"`
+	Secretbox *encryptionKeys `yaml:"secretbox,omitempty"`
+	Identity  *struct{}       `yaml:"identity,omitempty"`
+}
+
+type encryptionKeys struct {
+	Keys []encryptionKey `yaml:"keys"`
+}
+
+type encryptionKey struct {
+	Name   string `yaml:"name"`
+	Secret string `yaml:"secret"`
+}
+
+var (
+	encryptionConfig     string
+	encryptionConfigs    map[string]string
+	encryptionConfigLock sync.Mutex
+)
+
+// SyncEncryptionConfig is used to distribute the encryption provider config of kube-apiserver to the masters.
+// The config found on the masters is kept, so that the key is generated only once for a cluster.
+func SyncEncryptionConfig(mgr *manager.Manager) error {
+	if !mgr.Cluster.Kubernetes.Encryption.Enabled {
+		return nil
+	}
+
+	mgr.Logger.Infoln("Syncing encryption config")
+
+	if err := getEncryptionConfigs(mgr); err != nil {
+		return err
+	}
+	if encryptionConfig == "" {
+		key, err := newEncryptionKey()
+		if err != nil {
+			return err
+		}
+		provider, err := newEncryptionProvider(mgr.Cluster.Kubernetes.Encryption.Provider, key)
+		if err != nil {
+			return err
+		}
+		config, err := newEncryptionConfig(mgr, provider)
+		if err != nil {
+			return err
+		}
+		encryptionConfig = config
+	}
+	return mgr.RunTaskOnMasterNodes(syncEncryptionConfig, true)
+}
+
+// getEncryptionConfigs reads the encryption config from all the masters into encryptionConfig, it is empty if no master has one.
+// The masters without the config are new ones, the configs of the others must be the same, otherwise a rotation of the key failed
+// partway and the config of any of them may miss the key in use, so they are not overwritten.
+func getEncryptionConfigs(mgr *manager.Manager) error {
+	encryptionConfig = ""
+	encryptionConfigs = map[string]string{}
+	if err := mgr.RunTaskOnMasterNodes(getEncryptionConfig, true); err != nil {
+		return err
+	}
+	var nodes []string
+	for node, config := range encryptionConfigs {
+		nodes = append(nodes, node)
+		if encryptionConfig == "" {
+			encryptionConfig = config
+		}
+	}
+	for _, config := range encryptionConfigs {
+		if config != encryptionConfig {
+			sort.Strings(nodes)
+			return errors.New(fmt.Sprintf("The encryption configs of the masters %s are different, make %s the same on them first", strings.Join(nodes, ","), tmpl.EncryptionConfigPath))
+		}
+	}
+	return nil
+}
+
+func getEncryptionConfig(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) error {
+	output, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"if [ -f %s ]; then cat %s | base64 --wrap=0; fi\"", tmpl.EncryptionConfigPath, tmpl.EncryptionConfigPath), 1, false)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "Failed to get encryption config")
+	}
+	if strings.TrimSpace(output) == "" {
+		return nil
+	}
+	config, err := base64.StdEncoding.DecodeString(strings.TrimSpace(output))
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "Failed to decode encryption config")
+	}
+	encryptionConfigLock.Lock()
+	defer encryptionConfigLock.Unlock()
+	encryptionConfigs[node.Name] = string(config)
+	return nil
+}
+
+func syncEncryptionConfig(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) error {
+	return writeEncryptionConfig(mgr, node, encryptionConfig)
+}
+
+func writeEncryptionConfig(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg, config string) error {
+	syncCmd := fmt.Sprintf("mkdir -p %s && echo %s | base64 -d > %s && chmod 600 %s", tmpl.EncryptionDir, base64.StdEncoding.EncodeToString([]byte(config)), tmpl.EncryptionConfigPath, tmpl.EncryptionConfigPath)
+	if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s\"", syncCmd), 1, false); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to sync encryption config to %s", node.Name))
+	}
+	return nil
+}
+
+// RotateEncryptionKey is used to replace the key of the encryption at rest with a new one.
+// The new key is added for decryption first, then used for encryption, and the old keys are removed after the resources are rewritten with the new key.
+// kube-apiserver is restarted on the masters one by one after each change, so that all of them are able to read the resources written by the others.
+func RotateEncryptionKey(mgr *manager.Manager) error {
+	if !mgr.Cluster.Kubernetes.Encryption.Enabled {
+		return errors.New("The encryption at rest is not enabled in the cluster configuration")
+	}
+
+	if err := getEncryptionConfigs(mgr); err != nil {
+		return err
+	}
+	if encryptionConfig == "" {
+		return errors.New(fmt.Sprintf("Failed to find %s on the masters", tmpl.EncryptionConfigPath))
+	}
+	// the providers not supported by kk, such as aesgcm and kms, are rejected as they would be dropped when the config is written back
+	current := encryptionConfiguration{}
+	if err := yaml.UnmarshalStrict([]byte(encryptionConfig), &current); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to parse %s, only the aescbc, secretbox and identity providers are supported", tmpl.EncryptionConfigPath))
+	}
+	// the config may be edited by hand, the current key is required to be the first provider of each resource
+	for _, resource := range current.Resources {
+		if len(resource.Providers) == 0 {
+			return errors.New(fmt.Sprintf("No encryption provider of %s is found in %s", strings.Join(resource.Resources, ","), tmpl.EncryptionConfigPath))
+		}
+		for _, provider := range resource.Providers {
+			if provider == (encryptionProvider{}) {
+				return errors.New(fmt.Sprintf("An empty encryption provider of %s is found in %s", strings.Join(resource.Resources, ","), tmpl.EncryptionConfigPath))
+			}
+		}
+	}
+	key, err := newEncryptionKey()
+	if err != nil {
+		return err
+	}
+	provider, err := newEncryptionProvider(mgr.Cluster.Kubernetes.Encryption.Provider, key)
+	if err != nil {
+		return err
+	}
+
+	steps := []struct {
+		msg    string
+		config func(resource encryptionResource) []encryptionProvider
+	}{
+		{"Adding the new encryption key", func(resource encryptionResource) []encryptionProvider {
+			// the new key is the second one, it is used to decrypt only
+			return append(resource.Providers[:1:1], append([]encryptionProvider{provider}, resource.Providers[1:]...)...)
+		}},
+		{"Encrypting with the new encryption key", func(resource encryptionResource) []encryptionProvider {
+			// the old key is kept to decrypt the resources not rewritten yet
+			return append([]encryptionProvider{provider, resource.Providers[0]}, resource.Providers[2:]...)
+		}},
+	}
+	for _, step := range steps {
+		mgr.Logger.Infoln(step.msg)
+		for i := range current.Resources {
+			current.Resources[i].Providers = step.config(current.Resources[i])
+		}
+		if err := applyEncryptionConfig(mgr, &current); err != nil {
+			return err
+		}
+	}
+
+	mgr.Logger.Infoln("Rewriting the encrypted resources with the new encryption key")
+	if err := mgr.RunTaskOnMasterNodes(rewriteEncryptedResources, false); err != nil {
+		return err
+	}
+
+	mgr.Logger.Infoln("Removing the old encryption keys")
+	for i := range current.Resources {
+		current.Resources[i].Providers = []encryptionProvider{provider, {Identity: &struct{}{}}}
+	}
+	return applyEncryptionConfig(mgr, &current)
+}
+
+func applyEncryptionConfig(mgr *manager.Manager, config *encryptionConfiguration) error {
+	content, err := yaml.Marshal(config)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "Failed to generate encryption config")
+	}
+	encryptionConfig = string(content)
+	return mgr.RunTaskOnMasterNodes(applyEncryptionConfigOnNode, false)
+}
+
+func applyEncryptionConfigOnNode(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) error {
+	if err := writeEncryptionConfig(mgr, node, encryptionConfig); err != nil {
+		return err
+	}
+	return RestartStaticPods(mgr, node, KubeApiserver)
+}
+
+func rewriteEncryptedResources(mgr *manager.Manager, _ *kubekeyapiv1alpha1.HostCfg) error {
+	if mgr.Runner.Index != 0 {
+		return nil
+	}
+	for _, resource := range mgr.Cluster.Kubernetes.Encryption.Resources {
+		rewriteCmd := fmt.Sprintf("/usr/local/bin/kubectl get %s --all-namespaces -o json | /usr/local/bin/kubectl replace -f -", resource)
+		if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s\"", rewriteCmd), 2, false); err != nil {
+			return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to rewrite %s", resource))
+		}
+	}
+	return nil
+}
+
+func newEncryptionConfig(mgr *manager.Manager, provider encryptionProvider) (string, error) {
+	config := encryptionConfiguration{
+		APIVersion: "apiserver.config.k8s.io/v1",
+		Kind:       "EncryptionConfiguration",
+		Resources: []encryptionResource{{
+			Resources: mgr.Cluster.Kubernetes.Encryption.Resources,
+			Providers: []encryptionProvider{provider, {Identity: &struct{}{}}},
+		}},
+	}
+	content, err := yaml.Marshal(config)
+	if err != nil {
+		return "", errors.Wrap(errors.WithStack(err), "Failed to generate encryption config")
+	}
+	return string(content), nil
+}
+
+func newEncryptionProvider(providerType string, key encryptionKey) (encryptionProvider, error) {
+	keys := &encryptionKeys{Keys: []encryptionKey{key}}
+	switch providerType {
+	case "aescbc":
+		return encryptionProvider{AESCBC: keys}, nil
+	case "secretbox":
+		return encryptionProvider{Secretbox: keys}, nil
+	default:
+		return encryptionProvider{}, errors.New(fmt.Sprintf("Unsupported encryption provider: %s", providerType))
+	}
+}
+
+// newEncryptionKey generates a random 32-byte key, which is valid for both aescbc and secretbox.
+func newEncryptionKey() (encryptionKey, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return encryptionKey{}, errors.Wrap(errors.WithStack(err), "Failed to generate encryption key")
+	}
+	return encryptionKey{
+		Name:   fmt.Sprintf("key-%s", time.Now().Format("20060102150405")),
+		Secret: base64.StdEncoding.EncodeToString(secret),
+	}, nil
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+	"github.com/kubesphere/kubekey/pkg/util/manager"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// ConfigureKubeProxy is used to apply the configuration of kube-proxy to an existing cluster.
+// kubeadm only renders the kube-proxy ConfigMap when the cluster is created or upgraded, so the fields managed by kk are patched into the ConfigMap,
+// and the kube-proxy daemonset is restarted if the ConfigMap is changed.
+func ConfigureKubeProxy(mgr *manager.Manager) error {
+	mgr.Logger.Infoln("Configuring kube-proxy")
+
+	return mgr.RunTaskOnMasterNodes(configureKubeProxy, false)
+}
+
+func configureKubeProxy(mgr *manager.Manager, _ *kubekeyapiv1alpha1.HostCfg) error {
+	if mgr.Runner.Index != 0 {
+		return nil
+	}
+	getCmd := "/usr/local/bin/kubectl -n kube-system get configmap kube-proxy -o jsonpath='{.data.config\\.conf}' | base64 --wrap=0"
+	output, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s\"", getCmd), 3, false)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "Failed to get kube-proxy config")
+	}
+	content, err := base64.StdEncoding.DecodeString(strings.TrimSpace(output))
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "Failed to decode kube-proxy config")
+	}
+	current := map[string]interface{}{}
+	if err := yaml.Unmarshal(content, &current); err != nil {
+		return errors.Wrap(errors.WithStack(err), "Failed to parse kube-proxy config")
+	}
+
+	desired, err := kubeProxyConfigValues(mgr)
+	if err != nil {
+		return err
+	}
+	if !mergeConfigValues(current, desired) {
+		return nil
+	}
+
+	content, err = yaml.Marshal(current)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "Failed to generate kube-proxy config")
+	}
+	patch, err := json.Marshal(map[string]interface{}{"data": map[string]string{"config.conf": string(content)}})
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "Failed to generate kube-proxy config patch")
+	}
+	mgr.Logger.Infoln("Restarting kube-proxy")
+	patchCmd := fmt.Sprintf("echo %s | base64 -d > /etc/kubernetes/kube-proxy-config-patch.json && "+
+		"/usr/local/bin/kubectl -n kube-system patch configmap kube-proxy --type=merge --patch \\\"\\$(cat /etc/kubernetes/kube-proxy-config-patch.json)\\\" && "+
+		"/usr/local/bin/kubectl -n kube-system rollout restart daemonset kube-proxy && "+
+		"/usr/local/bin/kubectl -n kube-system rollout status daemonset kube-proxy --timeout=300s",
+		base64.StdEncoding.EncodeToString(patch))
+	if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s\"", patchCmd), 1, true); err != nil {
+		return errors.Wrap(errors.WithStack(err), "Failed to update kube-proxy config")
+	}
+	return nil
+}
+
+// kubeProxyConfigValues returns the fields of the KubeProxyConfiguration managed by kk.
+// The ipvs timeouts not given are 0s, which means kube-proxy leaves them as they are on the nodes.
+func kubeProxyConfigValues(mgr *manager.Manager) (map[string]interface{}, error) {
+	kubeProxy := mgr.Cluster.Kubernetes.KubeProxy
+	durationOrZero := func(d string) string {
+		if d == "" {
+			return "0s"
+		}
+		return d
+	}
+	values := map[string]interface{}{
+		"mode": mgr.Cluster.Kubernetes.ProxyMode,
+		"iptables": map[string]interface{}{
+			"masqueradeAll": mgr.Cluster.Kubernetes.MasqueradeAll,
+			"masqueradeBit": kubeProxy.IPTables.MasqueradeBit,
+			"syncPeriod":    kubeProxy.IPTables.SyncPeriod,
+			"minSyncPeriod": kubeProxy.IPTables.MinSyncPeriod,
+		},
+		"ipvs": map[string]interface{}{
+			"scheduler":     kubeProxy.IPVS.Scheduler,
+			"strictARP":     kubeProxy.IPVS.StrictARP,
+			"excludeCIDRs":  kubeProxy.IPVS.ExcludeCIDRs,
+			"syncPeriod":    kubeProxy.IPVS.SyncPeriod,
+			"minSyncPeriod": kubeProxy.IPVS.MinSyncPeriod,
+			"tcpTimeout":    durationOrZero(kubeProxy.IPVS.TCPTimeout),
+			"tcpFinTimeout": durationOrZero(kubeProxy.IPVS.TCPFinTimeout),
+			"udpTimeout":    durationOrZero(kubeProxy.IPVS.UDPTimeout),
+		},
+		"conntrack": map[string]interface{}{
+			"maxPerCore":            kubeProxy.Conntrack.MaxPerCore,
+			"min":                   kubeProxy.Conntrack.Min,
+			"tcpEstablishedTimeout": kubeProxy.Conntrack.TCPEstablishedTimeout,
+			"tcpCloseWaitTimeout":   kubeProxy.Conntrack.TCPCloseWaitTimeout,
+		},
+	}
+	// convert the values to the types of the parsed config for comparison
+	content, err := json.Marshal(values)
+	if err != nil {
+		return nil, errors.Wrap(errors.WithStack(err), "Failed to generate kube-proxy config")
+	}
+	values = map[string]interface{}{}
+	if err := json.Unmarshal(content, &values); err != nil {
+		return nil, errors.Wrap(errors.WithStack(err), "Failed to generate kube-proxy config")
+	}
+	return values, nil
+}
+
+// mergeConfigValues sets the desired values into the current config recursively, and returns true if the config is changed.
+// A null and an empty list are considered equal.
+func mergeConfigValues(current, desired map[string]interface{}) bool {
+	changed := false
+	for k, v := range desired {
+		if desiredMap, ok := v.(map[string]interface{}); ok {
+			currentMap, ok := current[k].(map[string]interface{})
+			if !ok {
+				currentMap = map[string]interface{}{}
+				current[k] = currentMap
+			}
+			if mergeConfigValues(currentMap, desiredMap) {
+				changed = true
+			}
+			continue
+		}
+		if isEmptyList(current[k]) && isEmptyList(v) {
+			continue
+		}
+		if !reflect.DeepEqual(current[k], v) {
+			current[k] = v
+			changed = true
+		}
+	}
+	return changed
+}
+
+func isEmptyList(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	list, ok := v.([]interface{})
+	return ok && len(list) == 0
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+	"github.com/kubesphere/kubekey/pkg/cluster/kubernetes/tmpl"
+	"github.com/kubesphere/kubekey/pkg/util/manager"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+const kubeletConfigPath = "/var/lib/kubelet/config.yaml"
+
+// ConfigureKubelet is used to apply the kubelet configuration of each node.
+// kubeadm writes the same kubelet configuration to all the nodes, so the reservations and eviction thresholds of each node are written to its config file,
+// and kubelet is restarted one node at a time if the config file is changed.
+func ConfigureKubelet(mgr *manager.Manager) error {
+	mgr.Logger.Infoln("Configuring kubelet")
+
+	return mgr.RunTaskOnK8sNodes(configureKubelet, false)
+}
+
+func configureKubelet(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) error {
+	output, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"cat %s | base64 --wrap=0\"", kubeletConfigPath), 1, false)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to read kubelet config of %s", node.Name))
+	}
+	content, err := base64.StdEncoding.DecodeString(strings.TrimSpace(output))
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to decode kubelet config of %s", node.Name))
+	}
+	current := map[string]interface{}{}
+	if err := yaml.Unmarshal(content, &current); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to parse kubelet config of %s", node.Name))
+	}
+
+	desired, err := kubeletConfigValues(mgr, mgr.Cluster.KubeletConfigOf(node))
+	if err != nil {
+		return err
+	}
+	changed := false
+	for k, v := range desired {
+		if !reflect.DeepEqual(current[k], v) {
+			current[k] = v
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	content, err = yaml.Marshal(current)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to generate kubelet config of %s", node.Name))
+	}
+	mgr.Logger.Infof("Restarting kubelet on %s [%s]\n", node.Name, node.InternalAddress)
+	writeCmd := fmt.Sprintf("echo %s | base64 -d > %s && systemctl restart kubelet", base64.StdEncoding.EncodeToString(content), kubeletConfigPath)
+	if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s\"", writeCmd), 1, false); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to update kubelet config of %s", node.Name))
+	}
+	// wait for kubelet to become healthy before moving on to the next node
+	healthzCmd := "for i in $(seq 1 60); do curl -sf http://127.0.0.1:10248/healthz && exit 0; sleep 2; done; exit 1"
+	if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s\"", healthzCmd), 0, false); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Kubelet on %s is not healthy after restarting", node.Name))
+	}
+	return nil
+}
+
+// kubeletConfigValues returns the fields of the kubelet config file managed by kk.
+func kubeletConfigValues(mgr *manager.Manager, kubeletCfg kubekeyapiv1alpha1.KubeletConfig) (map[string]interface{}, error) {
+	if tmpl.GetContainerRuntimeEndpoint(mgr) == "" {
+		// container log rotation is not supported by docker
+		kubeletCfg.ContainerLogMaxSize = ""
+		kubeletCfg.ContainerLogMaxFiles = 0
+	}
+	content, err := json.Marshal(kubeletCfg)
+	if err != nil {
+		return nil, errors.Wrap(errors.WithStack(err), "Failed to generate kubelet config")
+	}
+	values := map[string]interface{}{}
+	if err := json.Unmarshal(content, &values); err != nil {
+		return nil, errors.Wrap(errors.WithStack(err), "Failed to generate kubelet config")
+	}
+	return values, nil
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+	"github.com/kubesphere/kubekey/pkg/cluster/kubernetes/tmpl"
+	"github.com/kubesphere/kubekey/pkg/util/manager"
+	"github.com/pkg/errors"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
+)
+
+// DeployInternalLoadbalancer is used to deploy the internal load balancer of kube-apiserver after the nodes are joined.
+// With haproxy, the masters access the local kube-apiserver and the workers access all the kube-apiservers by the local haproxy.
+// With kube-vip, the VIP is held by one of the masters.
+func DeployInternalLoadbalancer(mgr *manager.Manager) error {
+	switch mgr.Cluster.ControlPlaneEndpoint.InternalLoadbalancer {
+	case kubekeyapiv1alpha1.InternalLoadbalancerHaproxy:
+		mgr.Logger.Infoln("Deploying haproxy as the internal load balancer")
+
+		return mgr.RunTaskOnK8sNodes(deployHaproxy, true)
+	case kubekeyapiv1alpha1.InternalLoadbalancerKubeVip:
+		mgr.Logger.Infoln("Deploying kube-vip as the internal load balancer")
+
+		return mgr.RunTaskOnMasterNodes(deployKubeVip, true)
+	}
+	return nil
+}
+
+func deployHaproxy(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) error {
+	if node.IsMaster {
+		return updateLBHost(mgr, node.InternalAddress)
+	}
+
+	haproxyCfg, err := tmpl.GenerateHaproxyCfg(mgr.MasterNodes, "127.0.0.1", mgr.Cluster.ControlPlaneEndpoint.Port)
+	if err != nil {
+		return err
+	}
+	haproxyManifest, err := tmpl.GenerateHaproxyManifest(mgr, haproxyCfg)
+	if err != nil {
+		return err
+	}
+	files := map[string]string{
+		tmpl.HaproxyCfgPath: haproxyCfg,
+		fmt.Sprintf("%s/haproxy.yaml", tmpl.StaticPodDir): haproxyManifest,
+	}
+	for path, content := range files {
+		syncCmd := fmt.Sprintf("mkdir -p %s %s && echo %s | base64 -d > %s", tmpl.HaproxyDir, tmpl.StaticPodDir, base64.StdEncoding.EncodeToString([]byte(content)), path)
+		if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s\"", syncCmd), 1, false); err != nil {
+			return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to sync %s to %s", path, node.Name))
+		}
+	}
+
+	// the domain of the control plane is switched to the local haproxy once it is ready
+	if err := waitForHaproxy(mgr, node); err != nil {
+		return err
+	}
+	return updateLBHost(mgr, "127.0.0.1")
+}
+
+func waitForHaproxy(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) error {
+	checkHealthCmd := fmt.Sprintf("curl -sSf http://127.0.0.1:%d/healthz", tmpl.HaproxyHealthPort)
+	for i := 20; i > 0; i-- {
+		if _, err := mgr.Runner.ExecuteCmd(checkHealthCmd, 0, false); err == nil {
+			break
+		} else if i == 1 {
+			return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to wait for haproxy to start on %s", node.Name))
+		}
+		time.Sleep(time.Second * 5)
+	}
+	return nil
+}
+
+// updateLBHost points the domain of the control plane to the given address in /etc/hosts.
+func updateLBHost(mgr *manager.Manager, address string) error {
+	domain := mgr.Cluster.ControlPlaneEndpoint.Domain
+	updateCmd := fmt.Sprintf("sed -i 's/^.*  %s$/%s  %s/' /etc/hosts", strings.Replace(domain, ".", "\\.", -1), address, domain)
+	if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s\"", updateCmd), 1, false); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to point %s to %s", domain, address))
+	}
+	return nil
+}
+
+func deployKubeVip(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) error {
+	return syncKubeVipManifest(mgr, node, "/etc/kubernetes/admin.conf")
+}
+
+// deployKubeVipForInit is used to deploy kube-vip before kubeadm init on the first master.
+// admin.conf is not bound to cluster-admin until kubeadm init is done since v1.29, so super-admin.conf is used instead,
+// and it is replaced by admin.conf when the internal load balancer is deployed after the nodes are joined.
+func deployKubeVipForInit(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) error {
+	kubeConfig := "/etc/kubernetes/admin.conf"
+	if versionutil.MustParseSemantic(mgr.Cluster.Kubernetes.Version).AtLeast(versionutil.MustParseSemantic("v1.29.0")) {
+		kubeConfig = "/etc/kubernetes/super-admin.conf"
+	}
+	return syncKubeVipManifest(mgr, node, kubeConfig)
+}
+
+func syncKubeVipManifest(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg, kubeConfig string) error {
+	iface, err := getInterface(mgr, node)
+	if err != nil {
+		return err
+	}
+	kubeVipManifest, err := tmpl.GenerateKubeVipManifest(mgr, iface, kubeConfig)
+	if err != nil {
+		return err
+	}
+	syncCmd := fmt.Sprintf("mkdir -p %s && echo %s | base64 -d > %s/kube-vip.yaml", tmpl.StaticPodDir, base64.StdEncoding.EncodeToString([]byte(kubeVipManifest)), tmpl.StaticPodDir)
+	if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s\"", syncCmd), 1, false); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to deploy kube-vip on %s", node.Name))
+	}
+	return nil
+}
+
+// getInterface returns the network interface of the internal address of the node, the VIP is bound to it.
+func getInterface(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) (string, error) {
+	output, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("ip -o addr show | grep ' %s/' | awk '{print $2}'", node.InternalAddress), 1, false)
+	if err != nil || strings.TrimSpace(output) == "" {
+		return "", errors.New(fmt.Sprintf("Failed to find the network interface of %s on %s", node.InternalAddress, node.Name))
+	}
+	return strings.Fields(output)[0], nil
+}
+
+// ConfigureLoadBalancer is used to install keepalived and haproxy on the hosts of the loadbalancer role group.
+// The backends of haproxy are all the masters, so it is called whenever the masters are changed.
+func ConfigureLoadBalancer(mgr *manager.Manager) error {
+	if len(mgr.LoadBalancerNodes) == 0 {
+		return nil
+	}
+
+	mgr.Logger.Infoln("Configuring keepalived and haproxy on the load balancers")
+
+	return mgr.RunTaskOnLoadBalancerNodes(configureLoadBalancer, true)
+}
+
+func configureLoadBalancer(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) error {
+	installCmd := "if [ -z \\$(which keepalived 2>/dev/null) ] || [ -z \\$(which haproxy 2>/dev/null) ]; then " +
+		"if [ ! -z \\$(which yum 2>/dev/null) ]; then yum install keepalived haproxy -y; " +
+		"elif [ ! -z \\$(which apt 2>/dev/null) ]; then apt update && apt install keepalived haproxy -y; " +
+		"else echo 'Unsupported package manager' && exit 1; fi; fi"
+	if output, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s\"", installCmd), 2, false); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to install keepalived and haproxy on %s:\n%s", node.Name, output))
+	}
+
+	iface, err := getInterface(mgr, node)
+	if err != nil {
+		return err
+	}
+	haproxyCfg, err := tmpl.GenerateHaproxyCfg(mgr.MasterNodes, "*", mgr.Cluster.ControlPlaneEndpoint.Port)
+	if err != nil {
+		return err
+	}
+	keepalivedCfg, err := tmpl.GenerateKeepalivedCfg(mgr, node, mgr.Runner.Index, iface)
+	if err != nil {
+		return err
+	}
+
+	for _, service := range []struct {
+		name, path, content string
+	}{
+		{"haproxy", tmpl.HaproxyServiceCfgPath, haproxyCfg},
+		{"keepalived", tmpl.KeepalivedCfgPath, keepalivedCfg},
+	} {
+		configureCmd := fmt.Sprintf("mkdir -p %s && echo %s | base64 -d > %s && systemctl enable %s && systemctl reload-or-restart %s",
+			filepath.Dir(service.path), base64.StdEncoding.EncodeToString([]byte(service.content)), service.path, service.name, service.name)
+		if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s\"", configureCmd), 1, false); err != nil {
+			return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to configure %s on %s", service.name, node.Name))
+		}
+	}
+
+	return waitForHaproxy(mgr, node)
+}
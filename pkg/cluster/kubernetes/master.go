@@ -18,11 +18,13 @@ package kubernetes
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
@@ -33,6 +35,7 @@ import (
 	"github.com/kubesphere/kubekey/pkg/util/manager"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
 )
 
 var (
@@ -125,8 +128,20 @@ func initKubernetesCluster(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCf
 			return errors.Wrap(errors.WithStack(err1), "Failed to generate kubeadm config")
 		}
 
+		ignorePreflightErrors := "FileExisting-crictl"
+		kubeVipEnabled := mgr.Cluster.ControlPlaneEndpoint.InternalLoadbalancer == kubekeyapiv1alpha1.InternalLoadbalancerKubeVip
+		if kubeVipEnabled {
+			// kube-vip must hold the VIP before kube-apiserver is accessed by the control plane endpoint
+			ignorePreflightErrors += ",DirAvailable--etc-kubernetes-manifests"
+		}
+
 		for i := 0; i < 3; i++ {
-			_, err2 := mgr.Runner.ExecuteCmd("sudo env PATH=$PATH /bin/sh -c \"/usr/local/bin/kubeadm init --config=/etc/kubernetes/kubeadm-config.yaml --ignore-preflight-errors=FileExisting-crictl\"", 0, true)
+			if kubeVipEnabled {
+				if err := deployKubeVipForInit(mgr, node); err != nil {
+					return err
+				}
+			}
+			_, err2 := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo env PATH=$PATH /bin/sh -c \"/usr/local/bin/kubeadm init --config=/etc/kubernetes/kubeadm-config.yaml --ignore-preflight-errors=%s\"", ignorePreflightErrors), 0, true)
 			if err2 != nil {
 				if i == 2 {
 					return errors.Wrap(errors.WithStack(err2), "Failed to init kubernetes cluster")
@@ -149,6 +164,9 @@ func initKubernetesCluster(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCf
 		if err := dns.CreateClusterDns(mgr); err != nil {
 			return err
 		}
+		if err := PatchKubeProxyArgs(mgr); err != nil {
+			return err
+		}
 		clusterIsExist = true
 		if err := getJoinNodesCmd(mgr); err != nil {
 			return err
@@ -178,15 +196,31 @@ func GetKubeConfig(mgr *manager.Manager) error {
 
 func removeMasterTaint(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) error {
 	if node.IsWorker {
-		removeMasterTaintCmd := fmt.Sprintf("sudo -E /bin/sh -c \"/usr/local/bin/kubectl taint nodes %s node-role.kubernetes.io/master=:NoSchedule-\"", node.Name)
-		_, err := mgr.Runner.ExecuteCmd(removeMasterTaintCmd, 5, true)
-		if err != nil {
-			return errors.Wrap(errors.WithStack(err), "Failed to remove master taint")
+		for _, key := range masterTaintKeys(mgr.Cluster.Kubernetes.Version) {
+			// the taint is removed only if it is present, kubectl fails if the taint is not found
+			removeMasterTaintCmd := fmt.Sprintf("sudo -E /bin/sh -c \"if /usr/local/bin/kubectl get node %s -o jsonpath='{.spec.taints[*].key}' | grep -qwF %s; then /usr/local/bin/kubectl taint nodes %s %s:NoSchedule-; fi\"", node.Name, key, node.Name, key)
+			if _, err := mgr.Runner.ExecuteCmd(removeMasterTaintCmd, 5, true); err != nil {
+				return errors.Wrap(errors.WithStack(err), "Failed to remove master taint")
+			}
 		}
 	}
 	return nil
 }
 
+// masterTaintKeys returns the keys of the taints added by kubeadm to the control plane nodes of the given version of kubernetes.
+// The control-plane taint is added besides the master one since v1.24, and the master taint is not added any more since v1.25.
+func masterTaintKeys(version string) []string {
+	v := versionutil.MustParseSemantic(version)
+	switch {
+	case v.AtLeast(versionutil.MustParseSemantic("v1.25.0")):
+		return []string{"node-role.kubernetes.io/control-plane"}
+	case v.AtLeast(versionutil.MustParseSemantic("v1.24.0")):
+		return []string{"node-role.kubernetes.io/master", "node-role.kubernetes.io/control-plane"}
+	default:
+		return []string{"node-role.kubernetes.io/master"}
+	}
+}
+
 func addWorkerLabel(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) error {
 	if node.IsWorker {
 		addWorkerLabelCmd := fmt.Sprintf("sudo -E /bin/sh -c \"/usr/local/bin/kubectl label --overwrite node %s node-role.kubernetes.io/worker=\"", node.Name)
@@ -263,6 +297,31 @@ func getJoinCmd(mgr *manager.Manager) error {
 	return nil
 }
 
+// PatchKubeProxyArgs is used to add the extra flags to kube-proxy.
+// kube-proxy is configured by kubeadm with a config file only, so its command is replaced after kubeadm creates or upgrades the daemonset.
+// The command is replaced even without extra flags, so the flags removed from the config are removed from kube-proxy too.
+func PatchKubeProxyArgs(mgr *manager.Manager) error {
+	command := []string{"/usr/local/bin/kube-proxy", "--config=/var/lib/kube-proxy/config.conf", "--hostname-override=$(NODE_NAME)"}
+	var args []string
+	for k, v := range mgr.Cluster.Kubernetes.KubeProxyArgs {
+		args = append(args, fmt.Sprintf("--%s=%s", k, v))
+	}
+	sort.Strings(args)
+	patch, err := json.Marshal([]map[string]interface{}{
+		{"op": "replace", "path": "/spec/template/spec/containers/0/command", "value": append(command, args...)},
+	})
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "Failed to generate kube-proxy patch")
+	}
+	patchCmd := fmt.Sprintf("echo %s | base64 -d > /etc/kubernetes/kube-proxy-patch.json && "+
+		"/usr/local/bin/kubectl -n kube-system patch daemonset kube-proxy --type=json --patch \\\"\\$(cat /etc/kubernetes/kube-proxy-patch.json)\\\"",
+		base64.StdEncoding.EncodeToString(patch))
+	if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s\"", patchCmd), 5, true); err != nil {
+		return errors.Wrap(errors.WithStack(err), "Failed to patch kube-proxy")
+	}
+	return nil
+}
+
 // PatchKubeadmSecret is used to patch etcd's certs for kubeadm-certs secret.
 func PatchKubeadmSecret(mgr *manager.Manager) error {
 	externalEtcdCerts := []string{"external-etcd-ca.crt", "external-etcd.crt", "external-etcd.key"}
@@ -275,6 +334,42 @@ func PatchKubeadmSecret(mgr *manager.Manager) error {
 	return nil
 }
 
+// UploadKubeadmConfig is used to upload the kubeadm configuration generated from the current cluster spec before joining nodes,
+// so that the new masters are given the same flags and volumes of the control plane, such as the authentication of kube-apiserver.
+// The custom kubeadm configuration in the work directory is managed by users and is left as it is.
+func UploadKubeadmConfig(mgr *manager.Manager) error {
+	if !clusterIsExist || util.IsExist(fmt.Sprintf("%s/kubeadm-config.yaml", mgr.WorkDir)) {
+		return nil
+	}
+
+	mgr.Logger.Infoln("Uploading kubeadm config")
+
+	return mgr.RunTaskOnMasterNodes(uploadKubeadmConfig, false)
+}
+
+func uploadKubeadmConfig(mgr *manager.Manager, _ *kubekeyapiv1alpha1.HostCfg) error {
+	if mgr.Runner.Index != 0 {
+		return nil
+	}
+	return UploadClusterConfiguration(mgr)
+}
+
+// UploadClusterConfiguration generates the kubeadm configuration on the current master and uploads the ClusterConfiguration of it to the cluster.
+func UploadClusterConfiguration(mgr *manager.Manager) error {
+	kubeadmCfg, err := tmpl.GenerateKubeadmCfg(mgr)
+	if err != nil {
+		return err
+	}
+	kubeadmCfgBase64 := base64.StdEncoding.EncodeToString([]byte(kubeadmCfg))
+	if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"mkdir -p /etc/kubernetes && echo %s | base64 -d > /etc/kubernetes/kubeadm-config.yaml\"", kubeadmCfgBase64), 1, false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "Failed to generate kubeadm config")
+	}
+	if _, err := mgr.Runner.ExecuteCmd("sudo -E /bin/sh -c \"/usr/local/bin/kubeadm init phase upload-config kubeadm --config=/etc/kubernetes/kubeadm-config.yaml\"", 2, false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "Failed to upload kubeadm config")
+	}
+	return nil
+}
+
 // JoinNodesToCluster is used to join node to Cluster.
 func JoinNodesToCluster(mgr *manager.Manager) error {
 	if mgr.InCluster {
@@ -293,6 +388,10 @@ func JoinNodesToCluster(mgr *manager.Manager) error {
 		return err
 	}
 
+	if err := mgr.RunTaskOnK8sNodes(addTaintsForNodes, true); err != nil {
+		return err
+	}
+
 	if mgr.InCluster {
 		if err := kubekeycontroller.UpdateClusterConditions(mgr, "Join nodes", mgr.Conditions[4].StartTime, metav1.Now(), true, 5); err != nil {
 			return err
@@ -333,8 +432,12 @@ func joinNodesToCluster(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg)
 }
 
 func addMaster(mgr *manager.Manager) error {
+	joinCmd, err := getNodeJoinCmd(mgr, clusterStatus["joinMasterCmd"])
+	if err != nil {
+		return err
+	}
 	for i := 0; i < 3; i++ {
-		_, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo env PATH=$PATH /bin/sh -c \"%s\"", clusterStatus["joinMasterCmd"]), 0, true)
+		_, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo env PATH=$PATH /bin/sh -c \"%s\"", joinCmd), 0, true)
 		if err != nil {
 			if i == 2 {
 				return errors.Wrap(errors.WithStack(err), "Failed to add master to cluster")
@@ -352,8 +455,12 @@ func addMaster(mgr *manager.Manager) error {
 }
 
 func addWorker(mgr *manager.Manager) error {
+	joinCmd, err := getNodeJoinCmd(mgr, clusterStatus["joinWorkerCmd"])
+	if err != nil {
+		return err
+	}
 	for i := 0; i < 3; i++ {
-		_, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo env PATH=$PATH /bin/sh -c \"%s\"", clusterStatus["joinWorkerCmd"]), 0, true)
+		_, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo env PATH=$PATH /bin/sh -c \"%s\"", joinCmd), 0, true)
 		if err != nil {
 			if i == 2 {
 				return errors.Wrap(errors.WithStack(err), "Failed to add worker to cluster")
@@ -380,6 +487,23 @@ func addWorker(mgr *manager.Manager) error {
 	return nil
 }
 
+// getNodeJoinCmd returns the command to join the current node.
+// If JoinConfiguration is patched, the join command is converted to a kubeadm configuration file with the patches applied.
+func getNodeJoinCmd(mgr *manager.Manager, joinCmd string) (string, error) {
+	if !tmpl.HasKubeadmCfgPatches(mgr.Cluster.Kubernetes.KubeadmConfigPatches, "JoinConfiguration") {
+		return joinCmd, nil
+	}
+	joinCfg, err := tmpl.GenerateKubeadmJoinCfg(mgr, joinCmd)
+	if err != nil {
+		return "", err
+	}
+	joinCfgBase64 := base64.StdEncoding.EncodeToString([]byte(joinCfg))
+	if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"mkdir -p /etc/kubernetes && echo %s | base64 -d > /etc/kubernetes/kubeadm-join-config.yaml\"", joinCfgBase64), 1, false); err != nil {
+		return "", errors.Wrap(errors.WithStack(err), "Failed to generate kubeadm join config")
+	}
+	return "/usr/local/bin/kubeadm join --config=/etc/kubernetes/kubeadm-join-config.yaml", nil
+}
+
 func loadKubeConfig(mgr *manager.Manager) error {
 	kubeConfigPath := filepath.Join(mgr.WorkDir, fmt.Sprintf("config-%s", mgr.ObjName))
 	kubeconfigStr, err := base64.StdEncoding.DecodeString(clusterStatus["kubeconfig"])
@@ -406,3 +530,18 @@ func addLabelsForNodes(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) e
 
 	return nil
 }
+
+func addTaintsForNodes(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) error {
+	for _, taint := range node.Taints {
+		taintStr := fmt.Sprintf("%s:%s", taint.Key, taint.Effect)
+		if taint.Value != "" {
+			taintStr = fmt.Sprintf("%s=%s:%s", taint.Key, taint.Value, taint.Effect)
+		}
+		addTaintCmd := fmt.Sprintf("sudo -E /bin/sh -c \"/usr/local/bin/kubectl taint --overwrite node %s %s\"", node.Name, taintStr)
+		if _, err := mgr.Runner.ExecuteCmd(addTaintCmd, 5, true); err != nil {
+			return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to add taint %s to node %s", taintStr, node.Name))
+		}
+	}
+
+	return nil
+}
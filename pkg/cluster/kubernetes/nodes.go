@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+	"github.com/kubesphere/kubekey/pkg/catalog"
 	"github.com/kubesphere/kubekey/pkg/cluster/kubernetes/tmpl"
 	"github.com/kubesphere/kubekey/pkg/util/manager"
 	"github.com/pkg/errors"
@@ -79,7 +80,7 @@ func SyncKubeBinaries(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) er
 	kubelet := "kubelet"
 	kubectl := "kubectl"
 	helm := "helm"
-	kubecni := fmt.Sprintf("cni-plugins-linux-%s-%s.tgz", node.Arch, kubekeyapiv1alpha1.DefaultCniVersion)
+	kubecni := fmt.Sprintf("cni-plugins-linux-%s-%s.tgz", node.Arch, catalog.Get().BinaryVersion(mgr.Cluster.Kubernetes.Version, "kubecni"))
 	binaryList := []string{kubeadm, kubelet, kubectl, helm, kubecni}
 
 	var cmdlist []string
@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"fmt"
+	"strings"
+
+	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+	"github.com/kubesphere/kubekey/pkg/cluster/kubernetes/tmpl"
+	"github.com/kubesphere/kubekey/pkg/util/manager"
+	"github.com/pkg/errors"
+)
+
+const (
+	KubeApiserver         = "kube-apiserver"
+	KubeControllerManager = "kube-controller-manager"
+	KubeScheduler         = "kube-scheduler"
+	Etcd                  = "etcd"
+)
+
+// staticPodHealthz returns the command to check the health of a static pod of the control plane and the output when it is healthy.
+func staticPodHealthz(mgr *manager.Manager, name string) (string, string) {
+	switch name {
+	case KubeApiserver:
+		return fmt.Sprintf("curl -sk https://127.0.0.1:%d/healthz", mgr.Cluster.ControlPlaneEndpoint.Port), "ok"
+	case KubeControllerManager:
+		return "curl -sk https://127.0.0.1:10257/healthz", "ok"
+	case KubeScheduler:
+		return "curl -sk https://127.0.0.1:10259/healthz", "ok"
+	default:
+		return "curl -s --cacert /etc/kubernetes/pki/etcd/ca.crt --cert /etc/kubernetes/pki/etcd/healthcheck-client.crt " +
+			"--key /etc/kubernetes/pki/etcd/healthcheck-client.key https://127.0.0.1:2379/health", "true"
+	}
+}
+
+// RestartStaticPods restarts the static pods of the control plane on the node one by one.
+// The manifest of each pod is moved out of the manifests directory and back, so kubelet recreates the pod whatever the container runtime is.
+func RestartStaticPods(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg, names ...string) error {
+	for _, name := range names {
+		manifest := fmt.Sprintf("%s/%s.yaml", tmpl.StaticPodDir, name)
+		backup := fmt.Sprintf("/etc/kubernetes/%s.yaml.kk", name)
+		healthz, healthy := staticPodHealthz(mgr, name)
+		restartCmd := strings.Join([]string{
+			fmt.Sprintf("mv -f %s %s", manifest, backup),
+			fmt.Sprintf("for i in \\$(seq 1 60); do %s >/dev/null || break; sleep 2; done", healthz),
+			fmt.Sprintf("mv -f %s %s", backup, manifest),
+			fmt.Sprintf("for i in \\$(seq 1 90); do %s | grep -q %s && exit 0; sleep 2; done; exit 1", healthz, healthy),
+		}, "; ")
+		mgr.Logger.Infof("Restarting %s on %s [%s]\n", name, node.Name, node.InternalAddress)
+		if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s\"", restartCmd), 0, false); err != nil {
+			return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to restart %s on %s", name, node.Name))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tmpl
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+	"github.com/kubesphere/kubekey/pkg/util"
+	"github.com/kubesphere/kubekey/pkg/util/manager"
+	"github.com/lithammer/dedent"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
+)
+
+const (
+	// AdmissionDir is the directory of the AdmissionConfiguration and the configs of the admission plugins on the masters.
+	AdmissionDir        = "/etc/kubernetes/admission"
+	AdmissionConfigPath = AdmissionDir + "/admission-config.yaml"
+)
+
+var (
+	// AdmissionConfigTempl defines the template of the AdmissionConfiguration of kube-apiserver, the config of each plugin is referred by path.
+	AdmissionConfigTempl = template.Must(template.New("admissionConfig").Parse(
+		dedent.Dedent(`apiVersion: {{ .APIVersion }}
+kind: AdmissionConfiguration
+plugins:
+{{- range .Plugins }}
+- name: {{ . }}
+  path: {{ $.Dir }}/{{ . }}.yaml
+{{- end }}
+    `)))
+
+	// PodSecurityConfigTempl defines the template of the cluster-wide defaults and exemptions of the PodSecurity admission plugin.
+	PodSecurityConfigTempl = template.Must(template.New("podSecurityConfig").Parse(
+		dedent.Dedent(`apiVersion: {{ .APIVersion }}
+kind: PodSecurityConfiguration
+defaults:
+  enforce: {{ .PodSecurity.Enforce }}
+  enforce-version: {{ .PodSecurity.EnforceVersion }}
+  audit: {{ .PodSecurity.Audit }}
+  audit-version: {{ .PodSecurity.AuditVersion }}
+  warn: {{ .PodSecurity.Warn }}
+  warn-version: {{ .PodSecurity.WarnVersion }}
+exemptions:
+  usernames: [{{ range $i, $v := .PodSecurity.ExemptUsernames }}{{ if $i }}, {{ end }}{{ printf "%q" $v }}{{ end }}]
+  runtimeClasses: [{{ range $i, $v := .PodSecurity.ExemptRuntimeClasses }}{{ if $i }}, {{ end }}{{ printf "%q" $v }}{{ end }}]
+  namespaces: [{{ range $i, $v := .PodSecurity.ExemptNamespaces }}{{ if $i }}, {{ end }}{{ printf "%q" $v }}{{ end }}]
+    `)))
+
+	// PodSecurityPolicyManifest defines the pod security policies installed with the PodSecurityPolicy admission plugin.
+	// The privileged policy is used by the nodes and the service accounts of kube-system, the baseline policy is used by all the authenticated users.
+	PodSecurityPolicyManifest = dedent.Dedent(`---
+apiVersion: policy/v1beta1
+kind: PodSecurityPolicy
+metadata:
+  name: kubekey-privileged
+  annotations:
+    seccomp.security.alpha.kubernetes.io/allowedProfileNames: "*"
+spec:
+  privileged: true
+  allowPrivilegeEscalation: true
+  allowedCapabilities: ["*"]
+  volumes: ["*"]
+  hostNetwork: true
+  hostPorts:
+  - min: 0
+    max: 65535
+  hostIPC: true
+  hostPID: true
+  runAsUser:
+    rule: RunAsAny
+  seLinux:
+    rule: RunAsAny
+  supplementalGroups:
+    rule: RunAsAny
+  fsGroup:
+    rule: RunAsAny
+---
+apiVersion: policy/v1beta1
+kind: PodSecurityPolicy
+metadata:
+  name: kubekey-baseline
+  annotations:
+    seccomp.security.alpha.kubernetes.io/allowedProfileNames: "*"
+spec:
+  privileged: false
+  allowPrivilegeEscalation: true
+  volumes: ["configMap", "emptyDir", "projected", "secret", "downwardAPI", "persistentVolumeClaim", "csi"]
+  hostNetwork: false
+  hostIPC: false
+  hostPID: false
+  runAsUser:
+    rule: RunAsAny
+  seLinux:
+    rule: RunAsAny
+  supplementalGroups:
+    rule: RunAsAny
+  fsGroup:
+    rule: RunAsAny
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: kubekey-psp-privileged
+rules:
+- apiGroups: ["policy"]
+  resources: ["podsecuritypolicies"]
+  resourceNames: ["kubekey-privileged"]
+  verbs: ["use"]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: kubekey-psp-baseline
+rules:
+- apiGroups: ["policy"]
+  resources: ["podsecuritypolicies"]
+  resourceNames: ["kubekey-baseline"]
+  verbs: ["use"]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: kubekey-psp-privileged-nodes
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: kubekey-psp-privileged
+subjects:
+- apiGroup: rbac.authorization.k8s.io
+  kind: Group
+  name: system:nodes
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: kubekey-psp-privileged
+  namespace: kube-system
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: kubekey-psp-privileged
+subjects:
+- apiGroup: rbac.authorization.k8s.io
+  kind: Group
+  name: system:serviceaccounts:kube-system
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: kubekey-psp-baseline
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: kubekey-psp-baseline
+subjects:
+- apiGroup: rbac.authorization.k8s.io
+  kind: Group
+  name: system:authenticated
+`)
+)
+
+// AdmissionPluginConfigPath returns the path of the config of the given admission plugin on the masters.
+func AdmissionPluginConfigPath(plugin string) string {
+	return fmt.Sprintf("%s/%s.yaml", AdmissionDir, plugin)
+}
+
+// GenerateAdmissionConfig is used to generate the AdmissionConfiguration referring the configs of the plugins.
+func GenerateAdmissionConfig(mgr *manager.Manager) (string, error) {
+	admission := &mgr.Cluster.Kubernetes.Admission
+	var plugins []string
+	for _, pluginCfg := range admission.PluginConfigs {
+		plugins = append(plugins, pluginCfg.Name)
+	}
+	if admission.PodSecurity.Enabled() {
+		plugins = append(plugins, "PodSecurity")
+	}
+
+	// apiserver.k8s.io/v1alpha1 is replaced by apiserver.config.k8s.io/v1 since v1.17
+	apiVersion := "apiserver.config.k8s.io/v1"
+	if versionutil.MustParseSemantic(mgr.Cluster.Kubernetes.Version).LessThan(versionutil.MustParseSemantic("v1.17.0")) {
+		apiVersion = "apiserver.k8s.io/v1alpha1"
+	}
+	return util.Render(AdmissionConfigTempl, util.Data{
+		"APIVersion": apiVersion,
+		"Dir":        AdmissionDir,
+		"Plugins":    plugins,
+	})
+}
+
+// GeneratePodSecurityConfig is used to generate the config of the PodSecurity admission plugin.
+func GeneratePodSecurityConfig(mgr *manager.Manager) (string, error) {
+	// PodSecurity is beta in v1.23 and v1.24, and GA since v1.25
+	apiVersion := "pod-security.admission.config.k8s.io/v1"
+	if versionutil.MustParseSemantic(mgr.Cluster.Kubernetes.Version).LessThan(versionutil.MustParseSemantic("v1.25.0")) {
+		apiVersion = "pod-security.admission.config.k8s.io/v1beta1"
+	}
+	return util.Render(PodSecurityConfigTempl, util.Data{
+		"APIVersion":  apiVersion,
+		"PodSecurity": mgr.Cluster.Kubernetes.Admission.PodSecurity,
+	})
+}
+
+// admissionArgs returns the flags and volumes of kube-apiserver for the admission plugins.
+// NodeRestriction enabled by kubeadm is kept, and the plugins configured are enabled as well.
+func admissionArgs(admission *kubekeyapiv1alpha1.Admission) (map[string]string, []kubekeyapiv1alpha1.HostPathMount) {
+	if len(admission.EnablePlugins) == 0 && len(admission.DisablePlugins) == 0 && !admission.PodSecurityPolicy && !admission.Configured() {
+		return nil, nil
+	}
+
+	plugins := []string{"NodeRestriction"}
+	appendPlugin := func(plugin string) {
+		for _, p := range plugins {
+			if p == plugin {
+				return
+			}
+		}
+		plugins = append(plugins, plugin)
+	}
+	for _, plugin := range admission.EnablePlugins {
+		appendPlugin(plugin)
+	}
+	for _, pluginCfg := range admission.PluginConfigs {
+		appendPlugin(pluginCfg.Name)
+	}
+	if admission.PodSecurityPolicy {
+		appendPlugin("PodSecurityPolicy")
+	}
+
+	args := map[string]string{
+		"enable-admission-plugins": strings.Join(plugins, ","),
+	}
+	if len(admission.DisablePlugins) > 0 {
+		args["disable-admission-plugins"] = strings.Join(admission.DisablePlugins, ",")
+	}
+	var volumes []kubekeyapiv1alpha1.HostPathMount
+	if admission.Configured() {
+		args["admission-control-config-file"] = AdmissionConfigPath
+		volumes = append(volumes, kubekeyapiv1alpha1.HostPathMount{Name: "admission-config", HostPath: AdmissionDir, MountPath: AdmissionDir, ReadOnly: true, PathType: "DirectoryOrCreate"})
+	}
+	return args, volumes
+}
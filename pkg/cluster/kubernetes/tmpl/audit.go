@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tmpl
+
+import (
+	"path/filepath"
+	"strconv"
+
+	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+	"github.com/lithammer/dedent"
+)
+
+const (
+	// AuditDir is the directory of the audit policy and the webhook config on the masters.
+	AuditDir               = "/etc/kubernetes/audit"
+	AuditPolicyPath        = AuditDir + "/policy.yaml"
+	AuditWebhookConfigPath = AuditDir + "/webhook-config.yaml"
+)
+
+// DefaultAuditPolicy is used if the audit policy is not given.
+// The requests to secrets, configmaps and token reviews are logged at Metadata level to avoid leaking sensitive data.
+var DefaultAuditPolicy = dedent.Dedent(`apiVersion: audit.k8s.io/v1
+kind: Policy
+omitStages:
+  - RequestReceived
+rules:
+  - level: None
+    users: ["system:kube-proxy"]
+    verbs: ["watch"]
+    resources:
+      - group: ""
+        resources: ["endpoints", "services", "services/status"]
+  - level: None
+    userGroups: ["system:nodes"]
+    verbs: ["get"]
+    resources:
+      - group: ""
+        resources: ["nodes", "nodes/status"]
+  - level: None
+    nonResourceURLs:
+      - /healthz*
+      - /livez*
+      - /readyz*
+      - /version
+  - level: None
+    resources:
+      - group: ""
+        resources: ["events"]
+  - level: Metadata
+    resources:
+      - group: ""
+        resources: ["secrets", "configmaps"]
+      - group: authentication.k8s.io
+        resources: ["tokenreviews"]
+  - level: Request
+    verbs: ["create", "update", "patch", "delete", "deletecollection"]
+  - level: Metadata
+`)
+
+// auditArgs returns the flags and volumes of kube-apiserver for the audit configuration.
+func auditArgs(audit *kubekeyapiv1alpha1.Audit) (map[string]string, []kubekeyapiv1alpha1.HostPathMount) {
+	args := map[string]string{
+		"audit-policy-file": AuditPolicyPath,
+	}
+	volumes := []kubekeyapiv1alpha1.HostPathMount{
+		{Name: "audit-policy", HostPath: AuditDir, MountPath: AuditDir, ReadOnly: true, PathType: "DirectoryOrCreate"},
+	}
+	switch audit.Backend {
+	case "webhook":
+		args["audit-webhook-config-file"] = AuditWebhookConfigPath
+	default:
+		logDir := filepath.Dir(audit.LogPath)
+		args["audit-log-path"] = audit.LogPath
+		args["audit-log-maxage"] = strconv.Itoa(audit.LogMaxAge)
+		args["audit-log-maxbackup"] = strconv.Itoa(audit.LogMaxBackup)
+		args["audit-log-maxsize"] = strconv.Itoa(audit.LogMaxSize)
+		volumes = append(volumes, kubekeyapiv1alpha1.HostPathMount{Name: "audit-log", HostPath: logDir, MountPath: logDir, PathType: "DirectoryOrCreate"})
+	}
+	return args, volumes
+}
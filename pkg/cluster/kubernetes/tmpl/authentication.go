@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tmpl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+)
+
+const (
+	// AuthnDir is the directory of the OIDC CA and the kubeconfig files of the webhooks on the masters.
+	AuthnDir                       = "/etc/kubernetes/authn"
+	OIDCCAPath                     = AuthnDir + "/oidc-ca.crt"
+	AuthnWebhookConfigPath         = AuthnDir + "/authentication-webhook-config.yaml"
+	AuthorizationWebhookConfigPath = AuthnDir + "/authorization-webhook-config.yaml"
+
+	// the webhook authorizer is consulted only if neither Node nor RBAC allows the request
+	defaultAuthorizationMode     = "Node,RBAC"
+	authorizationModeWithWebhook = defaultAuthorizationMode + ",Webhook"
+)
+
+// authenticationArgs returns the flags and volumes of kube-apiserver for the authentication configuration.
+func authenticationArgs(authn *kubekeyapiv1alpha1.Authentication) (map[string]string, []kubekeyapiv1alpha1.HostPathMount) {
+	args := map[string]string{}
+	if oidc := &authn.OIDC; oidc.Enabled() {
+		args["oidc-issuer-url"] = oidc.IssuerURL
+		args["oidc-client-id"] = oidc.ClientID
+		if oidc.UsernameClaim != "" {
+			args["oidc-username-claim"] = oidc.UsernameClaim
+		}
+		if oidc.UsernamePrefix != "" {
+			args["oidc-username-prefix"] = oidc.UsernamePrefix
+		}
+		if oidc.GroupsClaim != "" {
+			args["oidc-groups-claim"] = oidc.GroupsClaim
+		}
+		if oidc.GroupsPrefix != "" {
+			args["oidc-groups-prefix"] = oidc.GroupsPrefix
+		}
+		if len(oidc.RequiredClaims) > 0 {
+			var claims []string
+			for k, v := range oidc.RequiredClaims {
+				claims = append(claims, fmt.Sprintf("%s=%s", k, v))
+			}
+			sort.Strings(claims)
+			args["oidc-required-claim"] = strings.Join(claims, ",")
+		}
+		if len(oidc.SigningAlgs) > 0 {
+			args["oidc-signing-algs"] = strings.Join(oidc.SigningAlgs, ",")
+		}
+		if oidc.CA != "" || oidc.CAFile != "" {
+			args["oidc-ca-file"] = OIDCCAPath
+		}
+	}
+
+	if webhook := &authn.Webhook; webhook.Enabled() {
+		args["authentication-token-webhook-config-file"] = AuthnWebhookConfigPath
+		if webhook.CacheTTL != "" {
+			args["authentication-token-webhook-cache-ttl"] = webhook.CacheTTL
+		}
+		if webhook.Version != "" {
+			args["authentication-token-webhook-version"] = webhook.Version
+		}
+	}
+
+	if webhook := &authn.AuthorizationWebhook; webhook.Enabled() {
+		args["authorization-mode"] = authorizationModeWithWebhook
+		args["authorization-webhook-config-file"] = AuthorizationWebhookConfigPath
+		if webhook.CacheAuthorizedTTL != "" {
+			args["authorization-webhook-cache-authorized-ttl"] = webhook.CacheAuthorizedTTL
+		}
+		if webhook.CacheUnauthorizedTTL != "" {
+			args["authorization-webhook-cache-unauthorized-ttl"] = webhook.CacheUnauthorizedTTL
+		}
+		if webhook.Version != "" {
+			args["authorization-webhook-version"] = webhook.Version
+		}
+	}
+
+	volumes := []kubekeyapiv1alpha1.HostPathMount{
+		{Name: "authn-config", HostPath: AuthnDir, MountPath: AuthnDir, ReadOnly: true, PathType: "DirectoryOrCreate"},
+	}
+	return args, volumes
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tmpl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/kubesphere/kubekey/pkg/cluster/preinstall"
+	"github.com/kubesphere/kubekey/pkg/util"
+	"github.com/kubesphere/kubekey/pkg/util/manager"
+	"github.com/lithammer/dedent"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
+)
+
+// KubeletCsrApproverManifestTempl defines the template of kubelet-csr-approver, which approves the CSRs of the kubelet serving certificates
+// only if the node name and all the IPs requested belong to the hosts of the cluster.
+var KubeletCsrApproverManifestTempl = template.Must(template.New("kubeletCsrApprover").Parse(
+	dedent.Dedent(`---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: kubelet-csr-approver
+  namespace: kube-system
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: kubelet-csr-approver
+rules:
+- apiGroups: ["certificates.k8s.io"]
+  resources: ["certificatesigningrequests"]
+  verbs: ["get", "list", "watch"]
+- apiGroups: ["certificates.k8s.io"]
+  resources: ["certificatesigningrequests/approval"]
+  verbs: ["update"]
+- apiGroups: ["certificates.k8s.io"]
+  resources: ["signers"]
+  resourceNames: ["kubernetes.io/kubelet-serving"]
+  verbs: ["approve"]
+- apiGroups: ["coordination.k8s.io"]
+  resources: ["leases"]
+  verbs: ["create", "get", "update"]
+- apiGroups: [""]
+  resources: ["events"]
+  verbs: ["create"]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: kubelet-csr-approver
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: kubelet-csr-approver
+subjects:
+- kind: ServiceAccount
+  name: kubelet-csr-approver
+  namespace: kube-system
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: kubelet-csr-approver
+  namespace: kube-system
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: kubelet-csr-approver
+  template:
+    metadata:
+      labels:
+        app: kubelet-csr-approver
+    spec:
+      serviceAccountName: kubelet-csr-approver
+      priorityClassName: system-cluster-critical
+      nodeSelector:
+        {{ .NodeRoleLabel }}: ""
+      tolerations:
+      - key: node-role.kubernetes.io/master
+        effect: NoSchedule
+      - key: node-role.kubernetes.io/control-plane
+        effect: NoSchedule
+      - key: CriticalAddonsOnly
+        operator: Exists
+      containers:
+      - name: kubelet-csr-approver
+        image: {{ .Image }}
+        imagePullPolicy: IfNotPresent
+        env:
+        - name: PROVIDER_REGEX
+          value: {{ printf "%q" .ProviderRegex }}
+        - name: PROVIDER_IP_PREFIXES
+          value: {{ printf "%q" .ProviderIPPrefixes }}
+        - name: BYPASS_DNS_RESOLUTION
+          value: "true"
+        resources:
+          requests:
+            cpu: 10m
+            memory: 32Mi
+          limits:
+            cpu: 200m
+            memory: 128Mi
+    `)))
+
+// GenerateKubeletCsrApproverManifest is used to generate the manifest of kubelet-csr-approver.
+// The node names must match one of the k8s nodes exactly, and the IPs must be the internal addresses of them.
+func GenerateKubeletCsrApproverManifest(mgr *manager.Manager) (string, error) {
+	names := make([]string, 0, len(mgr.K8sNodes))
+	prefixes := make([]string, 0, len(mgr.K8sNodes))
+	for _, node := range mgr.K8sNodes {
+		names = append(names, regexp.QuoteMeta(node.Name))
+		prefixes = append(prefixes, fmt.Sprintf("%s/32", node.InternalAddress))
+		if node.InternalIPv6 != "" {
+			prefixes = append(prefixes, fmt.Sprintf("%s/128", node.InternalIPv6))
+		}
+	}
+
+	// the control plane nodes are labeled node-role.kubernetes.io/control-plane since v1.20, and the master label is removed since v1.24
+	nodeRoleLabel := "node-role.kubernetes.io/master"
+	if versionutil.MustParseSemantic(mgr.Cluster.Kubernetes.Version).AtLeast(versionutil.MustParseSemantic("v1.20.0")) {
+		nodeRoleLabel = "node-role.kubernetes.io/control-plane"
+	}
+
+	return util.Render(KubeletCsrApproverManifestTempl, util.Data{
+		"NodeRoleLabel":      nodeRoleLabel,
+		"Image":              preinstall.GetImage(mgr, "kubelet-csr-approver").ImageName(),
+		"ProviderRegex":      fmt.Sprintf("^(%s)$", strings.Join(names, "|")),
+		"ProviderIPPrefixes": strings.Join(prefixes, ","),
+	})
+}
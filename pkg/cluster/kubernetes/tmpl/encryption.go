@@ -0,0 +1,38 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tmpl
+
+import (
+	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+)
+
+const (
+	// EncryptionDir is the directory of the encryption provider config on the masters.
+	EncryptionDir        = "/etc/kubernetes/encryption"
+	EncryptionConfigPath = EncryptionDir + "/config.yaml"
+)
+
+// encryptionArgs returns the flags and volumes of kube-apiserver for the encryption at rest.
+func encryptionArgs() (map[string]string, []kubekeyapiv1alpha1.HostPathMount) {
+	args := map[string]string{
+		"encryption-provider-config": EncryptionConfigPath,
+	}
+	volumes := []kubekeyapiv1alpha1.HostPathMount{
+		{Name: "encryption-config", HostPath: EncryptionDir, MountPath: EncryptionDir, ReadOnly: true, PathType: "DirectoryOrCreate"},
+	}
+	return args, volumes
+}
@@ -18,6 +18,8 @@ package tmpl
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -27,14 +29,20 @@ import (
 	"github.com/kubesphere/kubekey/pkg/util/manager"
 	"github.com/lithammer/dedent"
 	"github.com/pkg/errors"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
 )
 
 // KubeadmCfgTempl defines the template of kubeadm configuration file.
 var KubeadmCfgTempl = template.Must(template.New("kubeadmCfg").Parse(
 	dedent.Dedent(`---
-apiVersion: kubeadm.k8s.io/v1beta2
+apiVersion: {{ .KubeadmAPIVersion }}
 kind: ClusterConfiguration
 etcd:
+{{- if .LocalEtcd }}
+  local:
+    imageRepository: {{ .EtcdRepo }}
+    imageTag: {{ .EtcdTag }}
+{{- else }}
   external:
     endpoints:
     {{- range .ExternalEtcd.Endpoints }}
@@ -43,8 +51,11 @@ etcd:
     caFile: {{ .ExternalEtcd.CaFile }}
     certFile: {{ .ExternalEtcd.CertFile }}
     keyFile: {{ .ExternalEtcd.KeyFile }}
+{{- end }}
 dns:
+  {{- if .CoreDNSType }}
   type: CoreDNS
+  {{- end }}
   imageRepository: {{ .CorednsRepo }}
   imageTag: {{ .CorednsTag }}
 imageRepository: {{ .ImageRepo }}
@@ -56,51 +67,39 @@ networking:
   dnsDomain: {{ .ClusterName }}
   podSubnet: {{ .PodSubnet }}
   serviceSubnet: {{ .ServiceSubnet }}
+{{- if .DualStackFeatureGates }}
+featureGates:
+  {{- range $k, $v := .DualStackFeatureGates }}
+  {{ $k }}: {{ $v }}
+  {{- end }}
+{{- end }}
 apiServer:
   extraArgs:
-    anonymous-auth: "true"
-    bind-address: 0.0.0.0
-    insecure-port: "0"
-    profiling: "false"
-    apiserver-count: "1"
-    endpoint-reconciler-type: lease
-    authorization-mode: Node,RBAC
-    enable-aggregator-routing: "false"
-    allow-privileged: "true"
-    storage-backend: etcd3
-    audit-log-maxage: "30"
-    audit-log-maxbackup: "10"
-    audit-log-maxsize: "100"
-    audit-log-path: /var/log/apiserver/audit.log
-    feature-gates: CSINodeInfo=true,VolumeSnapshotDataSource=true,ExpandCSIVolumes=true,RotateKubeletServerCertificate=true
+  {{- template "extraArgs" .ApiServerArgs }}
   certSANs:
     {{- range .CertSANs }}
     - {{ . }}
     {{- end }}
+  {{- if .ApiServerExtraVolumes }}
+  extraVolumes:
+  {{- template "extraVolumes" .ApiServerExtraVolumes }}
+  {{- end }}
 controllerManager:
   extraArgs:
-    node-cidr-mask-size: "{{ .NodeCidrMaskSize }}"
-    experimental-cluster-signing-duration: 87600h
-    bind-address: 127.0.0.1
-    profiling: "false"
-    port: "10252"
-    terminated-pod-gc-threshold: "10"
-    feature-gates: CSINodeInfo=true,VolumeSnapshotDataSource=true,ExpandCSIVolumes=true,RotateKubeletServerCertificate=true
+  {{- template "extraArgs" .ControllerManagerArgs }}
   extraVolumes:
-  - name: host-time
-    hostPath: /etc/localtime
-    mountPath: /etc/localtime
-    readOnly: true
+  {{- template "extraVolumes" .ControllerManagerExtraVolumes }}
 scheduler:
   extraArgs:
-    profiling: "false"
-    bind-address: 127.0.0.1
-    port: "10251"
-    feature-gates: CSINodeInfo=true,VolumeSnapshotDataSource=true,ExpandCSIVolumes=true,RotateKubeletClientCertificate=true,RotateKubeletServerCertificate=true
+  {{- template "extraArgs" .SchedulerArgs }}
+  {{- if .SchedulerExtraVolumes }}
+  extraVolumes:
+  {{- template "extraVolumes" .SchedulerExtraVolumes }}
+  {{- end }}
 
 {{- if .CriSock }}
 ---
-apiVersion: kubeadm.k8s.io/v1beta2
+apiVersion: {{ .KubeadmAPIVersion }}
 kind: InitConfiguration
 nodeRegistration:
   criSocket: {{ .CriSock }}
@@ -118,24 +117,43 @@ clientConnection:
 clusterCIDR: {{ .PodSubnet }}
 configSyncPeriod: 15m0s
 conntrack:
- maxPerCore: 32768
- min: 131072
- tcpCloseWaitTimeout: 1h0m0s
- tcpEstablishedTimeout: 24h0m0s
+ maxPerCore: {{ .KubeProxy.Conntrack.MaxPerCore }}
+ min: {{ .KubeProxy.Conntrack.Min }}
+ tcpCloseWaitTimeout: {{ .KubeProxy.Conntrack.TCPCloseWaitTimeout }}
+ tcpEstablishedTimeout: {{ .KubeProxy.Conntrack.TCPEstablishedTimeout }}
 enableProfiling: False
 healthzBindAddress: 0.0.0.0:10256
 iptables:
  masqueradeAll: {{ .MasqueradeAll }}
- masqueradeBit: 14
- minSyncPeriod: 0s
- syncPeriod: 30s
+ masqueradeBit: {{ .KubeProxy.IPTables.MasqueradeBit }}
+ minSyncPeriod: {{ .KubeProxy.IPTables.MinSyncPeriod }}
+ syncPeriod: {{ .KubeProxy.IPTables.SyncPeriod }}
 ipvs:
- excludeCIDRs: []
- minSyncPeriod: 0s
- scheduler: rr
- syncPeriod: 30s
- strictARP: False
+ excludeCIDRs:
+ {{- range .KubeProxy.IPVS.ExcludeCIDRs }}
+ - {{ . }}
+ {{- else }} []
+ {{- end }}
+ minSyncPeriod: {{ .KubeProxy.IPVS.MinSyncPeriod }}
+ scheduler: {{ .KubeProxy.IPVS.Scheduler }}
+ syncPeriod: {{ .KubeProxy.IPVS.SyncPeriod }}
+ strictARP: {{ .KubeProxy.IPVS.StrictARP }}
+ {{- if .KubeProxy.IPVS.TCPTimeout }}
+ tcpTimeout: {{ .KubeProxy.IPVS.TCPTimeout }}
+ {{- end }}
+ {{- if .KubeProxy.IPVS.TCPFinTimeout }}
+ tcpFinTimeout: {{ .KubeProxy.IPVS.TCPFinTimeout }}
+ {{- end }}
+ {{- if .KubeProxy.IPVS.UDPTimeout }}
+ udpTimeout: {{ .KubeProxy.IPVS.UDPTimeout }}
+ {{- end }}
 mode: {{ .ProxyMode }}
+{{- if .KubeProxyFeatureGates }}
+featureGates:
+  {{- range $k, $v := .KubeProxyFeatureGates }}
+  {{ $k }}: {{ $v }}
+  {{- end }}
+{{- end }}
 
 ---
 apiVersion: kubelet.config.k8s.io/v1beta1
@@ -145,57 +163,399 @@ clusterDNS:
 - {{ .ClusterIP }}
 maxPods: {{ .MaxPods }}
 rotateCertificates: true
+{{- if .Kubelet.ServerTLSBootstrap }}
+serverTLSBootstrap: true
+{{- end }}
 {{- if .CriSock }}
-containerLogMaxSize: 5Mi
-containerLogMaxFiles: 3
+containerLogMaxSize: {{ .Kubelet.ContainerLogMaxSize }}
+containerLogMaxFiles: {{ .Kubelet.ContainerLogMaxFiles }}
 {{- if .CgroupDriver }}
 cgroupDriver: systemd
 {{- end }}
 {{- end }}
 kubeReserved:
-  cpu: 200m
-  memory: 250Mi
+  {{- range $k, $v := .Kubelet.KubeReserved }}
+  {{ $k }}: {{ $v }}
+  {{- end }}
 systemReserved:
-  cpu: 200m
-  memory: 250Mi
+  {{- range $k, $v := .Kubelet.SystemReserved }}
+  {{ $k }}: {{ $v }}
+  {{- end }}
 evictionHard:
-  memory.available: 5%
+  {{- range $k, $v := .Kubelet.EvictionHard }}
+  {{ $k }}: {{ printf "%q" $v }}
+  {{- end }}
 evictionSoft:
-  memory.available: 10%
-evictionSoftGracePeriod: 
-  memory.available: 2m
-evictionMaxPodGracePeriod: 120
-evictionPressureTransitionPeriod: 30s
+  {{- range $k, $v := .Kubelet.EvictionSoft }}
+  {{ $k }}: {{ printf "%q" $v }}
+  {{- end }}
+evictionSoftGracePeriod:
+  {{- range $k, $v := .Kubelet.EvictionSoftGracePeriod }}
+  {{ $k }}: {{ $v }}
+  {{- end }}
+evictionMaxPodGracePeriod: {{ .Kubelet.EvictionMaxPodGracePeriod }}
+evictionPressureTransitionPeriod: {{ .Kubelet.EvictionPressureTransitionPeriod }}
 featureGates:
-  CSINodeInfo: true
-  VolumeSnapshotDataSource: true
-  ExpandCSIVolumes: true
-  RotateKubeletClientCertificate: true
-  RotateKubeletServerCertificate: true
+  {{- range $k, $v := .KubeletFeatureGates }}
+  {{ $k }}: {{ $v }}
+  {{- end }}
+
+{{- define "extraArgs" }}
+  {{- if .List }}
+  {{- range $k, $v := .Args }}
+  - name: {{ $k }}
+    value: {{ printf "%q" $v }}
+  {{- end }}
+  {{- else }}
+  {{- range $k, $v := .Args }}
+    {{ $k }}: {{ printf "%q" $v }}
+  {{- end }}
+  {{- end }}
+{{- end }}
+
+{{- define "extraVolumes" }}
+  {{- range . }}
+  - name: {{ .Name }}
+    hostPath: {{ .HostPath }}
+    mountPath: {{ .MountPath }}
+    readOnly: {{ .ReadOnly }}
+    {{- if .PathType }}
+    pathType: {{ .PathType }}
+    {{- end }}
+  {{- end }}
+{{- end }}
     `)))
 
+// KubeadmJoinCfgTempl defines the template of kubeadm configuration file to join a node, it is used only if JoinConfiguration is patched.
+var KubeadmJoinCfgTempl = template.Must(template.New("kubeadmJoinCfg").Parse(
+	dedent.Dedent(`---
+apiVersion: {{ .KubeadmAPIVersion }}
+kind: JoinConfiguration
+discovery:
+  bootstrapToken:
+    apiServerEndpoint: {{ .ApiServerEndpoint }}
+    token: {{ .Token }}
+    caCertHashes:
+    {{- range .CaCertHashes }}
+    - {{ . }}
+    {{- end }}
+{{- if .CriSock }}
+nodeRegistration:
+  criSocket: {{ .CriSock }}
+{{- end }}
+{{- if .CertificateKey }}
+controlPlane:
+  certificateKey: {{ .CertificateKey }}
+{{- end }}
+    `)))
+
+const (
+	// KubeadmV1beta2 is the apiVersion of the kubeadm configuration used before v1.22.
+	KubeadmV1beta2 = "kubeadm.k8s.io/v1beta2"
+	// KubeadmV1beta3 is the apiVersion of the kubeadm configuration used since v1.22.
+	KubeadmV1beta3 = "kubeadm.k8s.io/v1beta3"
+	// KubeadmV1beta4 is the apiVersion of the kubeadm configuration used since v1.31, the extra args of the components are given as a list of names and values.
+	KubeadmV1beta4 = "kubeadm.k8s.io/v1beta4"
+)
+
+var (
+	// defaultFeatureGates are the feature gates enabled for kube-apiserver and kube-controller-manager by default.
+	defaultFeatureGates = map[string]bool{
+		"CSINodeInfo":                    true,
+		"VolumeSnapshotDataSource":       true,
+		"ExpandCSIVolumes":               true,
+		"RotateKubeletServerCertificate": true,
+	}
+	// defaultKubeletFeatureGates are the feature gates enabled for kube-scheduler and kubelet by default.
+	defaultKubeletFeatureGates = map[string]bool{
+		"CSINodeInfo":                    true,
+		"VolumeSnapshotDataSource":       true,
+		"ExpandCSIVolumes":               true,
+		"RotateKubeletClientCertificate": true,
+		"RotateKubeletServerCertificate": true,
+	}
+	// featureGateGAVersions are the versions in which the default feature gates become GA, they are not set any more since then.
+	// RotateKubeletServerCertificate is still a beta gate, so it is always set.
+	featureGateGAVersions = map[string]string{
+		"CSINodeInfo":                    "v1.17.0",
+		"VolumeSnapshotDataSource":       "v1.20.0",
+		"ExpandCSIVolumes":               "v1.24.0",
+		"RotateKubeletClientCertificate": "v1.19.0",
+	}
+)
+
+// extraArgs are the flags of a control plane component, rendered as a map or as a list of names and values according to the kubeadm apiVersion.
+type extraArgs struct {
+	Args map[string]string
+	List bool
+}
+
+// KubeadmAPIVersion returns the apiVersion of the kubeadm configuration for the given version of kubernetes.
+func KubeadmAPIVersion(version string) string {
+	v := versionutil.MustParseSemantic(version)
+	switch {
+	case v.AtLeast(versionutil.MustParseSemantic("v1.31.0")):
+		return KubeadmV1beta4
+	case v.AtLeast(versionutil.MustParseSemantic("v1.22.0")):
+		return KubeadmV1beta3
+	default:
+		return KubeadmV1beta2
+	}
+}
+
 // GenerateKubeadmCfg create kubeadm configuration file to initialize the cluster.
 func GenerateKubeadmCfg(mgr *manager.Manager) (string, error) {
 	// generate etcd configuration
 	var externalEtcd kubekeyapiv1alpha1.ExternalEtcd
-	var endpointsList []string
-	var caFile, certFile, keyFile, containerRuntimeEndpoint string
+	externalEtcd.Endpoints = mgr.Cluster.EtcdEndpoints(mgr.EtcdNodes)
+	externalEtcd.CaFile, externalEtcd.CertFile, externalEtcd.KeyFile = mgr.Cluster.EtcdClientCerts(mgr.MasterNodes[0].Name)
+
+	containerRuntimeEndpoint := GetContainerRuntimeEndpoint(mgr)
+
+	cgroupDriver, err := getKubeletCgroupDriver(mgr)
+	if err != nil {
+		return "", err
+	}
+
+	version := versionutil.MustParseSemantic(mgr.Cluster.Kubernetes.Version)
+	kubeadmAPIVersion := KubeadmAPIVersion(mgr.Cluster.Kubernetes.Version)
+
+	dualStackGates := dualStackFeatureGates(mgr)
+	featureGates := mergeFeatureGates(mergeFeatureGates(featureGatesOf(defaultFeatureGates, version), dualStackGates), mgr.Cluster.Kubernetes.FeatureGates)
+	kubeletFeatureGates := mergeFeatureGates(mergeFeatureGates(featureGatesOf(defaultKubeletFeatureGates, version), dualStackGates), mgr.Cluster.Kubernetes.FeatureGates)
+
+	apiServerArgs := map[string]string{
+		"anonymous-auth":            "true",
+		"bind-address":              "0.0.0.0",
+		"profiling":                 "false",
+		"apiserver-count":           "1",
+		"endpoint-reconciler-type":  "lease",
+		"authorization-mode":        defaultAuthorizationMode,
+		"enable-aggregator-routing": "false",
+		"allow-privileged":          "true",
+		"storage-backend":           "etcd3",
+		"feature-gates":             featureGatesString(featureGates),
+	}
+	if version.LessThan(versionutil.MustParseSemantic("v1.24.0")) {
+		// the insecure port is removed since v1.24
+		apiServerArgs["insecure-port"] = "0"
+	}
+	apiServerExtraVolumes := mgr.Cluster.Kubernetes.ApiServerExtraVolumes
+	if mgr.Cluster.Kubernetes.Audit.Enabled {
+		args, volumes := auditArgs(&mgr.Cluster.Kubernetes.Audit)
+		apiServerArgs = mergeArgs(apiServerArgs, args)
+		apiServerExtraVolumes = mergeExtraVolumes(volumes, apiServerExtraVolumes)
+	}
+	if mgr.Cluster.Kubernetes.Encryption.Enabled {
+		args, volumes := encryptionArgs()
+		apiServerArgs = mergeArgs(apiServerArgs, args)
+		apiServerExtraVolumes = mergeExtraVolumes(volumes, apiServerExtraVolumes)
+	}
+	if mgr.Cluster.Kubernetes.Authentication.Enabled() {
+		args, volumes := authenticationArgs(&mgr.Cluster.Kubernetes.Authentication)
+		apiServerArgs = mergeArgs(apiServerArgs, args)
+		apiServerExtraVolumes = mergeExtraVolumes(volumes, apiServerExtraVolumes)
+	}
+	if args, volumes := admissionArgs(&mgr.Cluster.Kubernetes.Admission); len(args) > 0 {
+		apiServerArgs = mergeArgs(apiServerArgs, args)
+		apiServerExtraVolumes = mergeExtraVolumes(volumes, apiServerExtraVolumes)
+	}
+	if mgr.Cluster.ExternalCloudProvider() {
+		apiServerArgs["cloud-provider"] = kubekeyapiv1alpha1.CloudProviderExternal
+	}
+	apiServerArgs = mergeArgs(apiServerArgs, mgr.Cluster.Kubernetes.ApiServerArgs)
+	controllerManagerArgs := map[string]string{
+		"node-cidr-mask-size":         strconv.Itoa(mgr.Cluster.Kubernetes.NodeCidrMaskSize),
+		"bind-address":                "127.0.0.1",
+		"profiling":                   "false",
+		"terminated-pod-gc-threshold": "10",
+		"feature-gates":               featureGatesString(featureGates),
+	}
+	if version.AtLeast(versionutil.MustParseSemantic("v1.19.0")) {
+		controllerManagerArgs["cluster-signing-duration"] = "87600h"
+	} else {
+		controllerManagerArgs["experimental-cluster-signing-duration"] = "87600h"
+	}
+	if mgr.Cluster.Network.DualStack() {
+		// the mask size of each family is given separately in a dual-stack cluster
+		delete(controllerManagerArgs, "node-cidr-mask-size")
+		controllerManagerArgs["node-cidr-mask-size-ipv4"] = strconv.Itoa(mgr.Cluster.Kubernetes.NodeCidrMaskSize)
+		controllerManagerArgs["node-cidr-mask-size-ipv6"] = strconv.Itoa(mgr.Cluster.Kubernetes.NodeCidrMaskSizeIPv6)
+	}
+	if mgr.Cluster.ExternalCloudProvider() {
+		// the cloud controllers are run by the external cloud-controller-manager
+		controllerManagerArgs["cloud-provider"] = kubekeyapiv1alpha1.CloudProviderExternal
+	}
+	schedulerArgs := map[string]string{
+		"profiling":     "false",
+		"bind-address":  "127.0.0.1",
+		"feature-gates": featureGatesString(kubeletFeatureGates),
+	}
+	if version.LessThan(versionutil.MustParseSemantic("v1.22.0")) {
+		// kube-controller-manager and kube-scheduler serve on the insecure ports only before v1.22
+		controllerManagerArgs["port"] = "10252"
+		schedulerArgs["port"] = "10251"
+	}
+	controllerManagerArgs = mergeArgs(controllerManagerArgs, mgr.Cluster.Kubernetes.ControllerManagerArgs)
+	schedulerArgs = mergeArgs(schedulerArgs, mgr.Cluster.Kubernetes.SchedulerArgs)
+	for _, args := range []map[string]string{apiServerArgs, controllerManagerArgs, schedulerArgs} {
+		// all the default feature gates are GA in the recent versions
+		if args["feature-gates"] == "" {
+			delete(args, "feature-gates")
+		}
+	}
+	controllerManagerExtraVolumes := mergeExtraVolumes([]kubekeyapiv1alpha1.HostPathMount{
+		{Name: "host-time", HostPath: "/etc/localtime", MountPath: "/etc/localtime", ReadOnly: true},
+	}, mgr.Cluster.Kubernetes.ControllerManagerExtraVolumes)
 
-	for _, host := range mgr.EtcdNodes {
-		endpoint := fmt.Sprintf("https://%s:%s", host.InternalAddress, kubekeyapiv1alpha1.DefaultEtcdPort)
-		endpointsList = append(endpointsList, endpoint)
+	kubeadmCfg, err := util.Render(KubeadmCfgTempl, util.Data{
+		"KubeadmAPIVersion":             kubeadmAPIVersion,
+		"CoreDNSType":                   kubeadmAPIVersion == KubeadmV1beta2,
+		"ImageRepo":                     strings.TrimSuffix(preinstall.GetImage(mgr, "kube-apiserver").ImageRepo(), "/kube-apiserver"),
+		"CorednsRepo":                   strings.TrimSuffix(preinstall.GetImage(mgr, "coredns").ImageRepo(), "/coredns"),
+		"CorednsTag":                    preinstall.GetImage(mgr, "coredns").Tag,
+		"Version":                       mgr.Cluster.Kubernetes.Version,
+		"ClusterName":                   mgr.Cluster.Kubernetes.ClusterName,
+		"ControlPlaneEndpoint":          fmt.Sprintf("%s:%d", mgr.Cluster.ControlPlaneEndpoint.Domain, mgr.Cluster.ControlPlaneEndpoint.Port),
+		"PodSubnet":                     mgr.Cluster.Network.KubePodsCIDR,
+		"ServiceSubnet":                 mgr.Cluster.Network.KubeServiceCIDR,
+		"CertSANs":                      mgr.Cluster.GenerateCertSANs(),
+		"ExternalEtcd":                  externalEtcd,
+		"LocalEtcd":                     mgr.Cluster.Etcd.Type == kubekeyapiv1alpha1.EtcdTypeKubeadm,
+		"EtcdRepo":                      strings.TrimSuffix(preinstall.GetImage(mgr, "etcd").ImageRepo(), "/etcd"),
+		"EtcdTag":                       preinstall.GetImage(mgr, "etcd").Tag,
+		"ClusterIP":                     "169.254.25.10",
+		"MasqueradeAll":                 mgr.Cluster.Kubernetes.MasqueradeAll,
+		"MaxPods":                       mgr.Cluster.Kubernetes.MaxPods,
+		"ProxyMode":                     mgr.Cluster.Kubernetes.ProxyMode,
+		"KubeProxy":                     mgr.Cluster.Kubernetes.KubeProxy,
+		"CriSock":                       containerRuntimeEndpoint,
+		"CgroupDriver":                  cgroupDriver,
+		"ApiServerArgs":                 extraArgs{Args: apiServerArgs, List: kubeadmAPIVersion == KubeadmV1beta4},
+		"ControllerManagerArgs":         extraArgs{Args: controllerManagerArgs, List: kubeadmAPIVersion == KubeadmV1beta4},
+		"SchedulerArgs":                 extraArgs{Args: schedulerArgs, List: kubeadmAPIVersion == KubeadmV1beta4},
+		"ApiServerExtraVolumes":         apiServerExtraVolumes,
+		"ControllerManagerExtraVolumes": controllerManagerExtraVolumes,
+		"SchedulerExtraVolumes":         mgr.Cluster.Kubernetes.SchedulerExtraVolumes,
+		"KubeletFeatureGates":           kubeletFeatureGates,
+		"Kubelet":                       mgr.Cluster.KubeletConfigOf(nil),
+		"KubeProxyFeatureGates":         mergeFeatureGates(dualStackGates, mgr.Cluster.Kubernetes.FeatureGates),
+		"DualStackFeatureGates":         dualStackGates,
+	})
+	if err != nil {
+		return "", err
 	}
-	externalEtcd.Endpoints = endpointsList
+	return PatchKubeadmCfg(kubeadmCfg, mgr.Cluster.Kubernetes.KubeadmConfigPatches, "InitConfiguration")
+}
 
-	caFile = "/etc/ssl/etcd/ssl/ca.pem"
-	certFile = fmt.Sprintf("/etc/ssl/etcd/ssl/node-%s.pem", mgr.MasterNodes[0].Name)
-	keyFile = fmt.Sprintf("/etc/ssl/etcd/ssl/node-%s-key.pem", mgr.MasterNodes[0].Name)
+// GenerateKubeadmJoinCfg create kubeadm configuration file to join a node from the given join command.
+func GenerateKubeadmJoinCfg(mgr *manager.Manager, joinCmd string) (string, error) {
+	data := util.Data{
+		"KubeadmAPIVersion": KubeadmAPIVersion(mgr.Cluster.Kubernetes.Version),
+		"CriSock":           GetContainerRuntimeEndpoint(mgr),
+	}
+	var caCertHashes []string
+	fields := strings.Fields(joinCmd)
+	for i, field := range fields {
+		if i+1 < len(fields) {
+			switch field {
+			case "join":
+				data["ApiServerEndpoint"] = fields[i+1]
+			case "--token":
+				data["Token"] = fields[i+1]
+			case "--discovery-token-ca-cert-hash":
+				caCertHashes = append(caCertHashes, fields[i+1])
+			case "--certificate-key":
+				data["CertificateKey"] = fields[i+1]
+			}
+		}
+	}
+	data["CaCertHashes"] = caCertHashes
+	if data["ApiServerEndpoint"] == nil || data["Token"] == nil {
+		return "", errors.New(fmt.Sprintf("Failed to parse join command: %s", joinCmd))
+	}
 
-	externalEtcd.CaFile = caFile
-	externalEtcd.CertFile = certFile
-	externalEtcd.KeyFile = keyFile
+	joinCfg, err := util.Render(KubeadmJoinCfgTempl, data)
+	if err != nil {
+		return "", err
+	}
+	return PatchKubeadmCfg(joinCfg, mgr.Cluster.Kubernetes.KubeadmConfigPatches)
+}
 
-	// generate cri configuration
+// dualStackFeatureGates returns the feature gates required by a dual-stack cluster, which are enabled by default since v1.21.
+func dualStackFeatureGates(mgr *manager.Manager) map[string]bool {
+	if !mgr.Cluster.Network.DualStack() {
+		return nil
+	}
+	if versionutil.MustParseSemantic(mgr.Cluster.Kubernetes.Version).AtLeast(versionutil.MustParseSemantic("v1.21.0")) {
+		return nil
+	}
+	return map[string]bool{"IPv6DualStack": true}
+}
+
+// featureGatesOf returns the default feature gates which are not GA in the given version of kubernetes.
+func featureGatesOf(defaultGates map[string]bool, version *versionutil.Version) map[string]bool {
+	gates := make(map[string]bool, len(defaultGates))
+	for k, v := range defaultGates {
+		if gaVersion, ok := featureGateGAVersions[k]; ok && version.AtLeast(versionutil.MustParseSemantic(gaVersion)) {
+			continue
+		}
+		gates[k] = v
+	}
+	return gates
+}
+
+// mergeArgs merges the flags given by users over the default flags of a component.
+func mergeArgs(defaultArgs, args map[string]string) map[string]string {
+	for k, v := range args {
+		defaultArgs[k] = v
+	}
+	return defaultArgs
+}
+
+// mergeFeatureGates merges the feature gates given by users over the default feature gates.
+func mergeFeatureGates(defaultGates, gates map[string]bool) map[string]bool {
+	merged := make(map[string]bool, len(defaultGates)+len(gates))
+	for k, v := range defaultGates {
+		merged[k] = v
+	}
+	for k, v := range gates {
+		merged[k] = v
+	}
+	return merged
+}
+
+// featureGatesString returns the feature gates in the format of the "--feature-gates" flag.
+func featureGatesString(gates map[string]bool) string {
+	var list []string
+	for k, v := range gates {
+		list = append(list, fmt.Sprintf("%s=%t", k, v))
+	}
+	sort.Strings(list)
+	return strings.Join(list, ",")
+}
+
+// mergeExtraVolumes appends the volumes given by users to the default volumes, a default volume is replaced by the one with the same name.
+func mergeExtraVolumes(defaultVolumes, volumes []kubekeyapiv1alpha1.HostPathMount) []kubekeyapiv1alpha1.HostPathMount {
+	var merged []kubekeyapiv1alpha1.HostPathMount
+	for _, defaultVolume := range defaultVolumes {
+		overridden := false
+		for _, volume := range volumes {
+			if volume.Name == defaultVolume.Name {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			merged = append(merged, defaultVolume)
+		}
+	}
+	return append(merged, volumes...)
+}
+
+// GetContainerRuntimeEndpoint returns the cri socket of the container runtime, it is empty for docker.
+func GetContainerRuntimeEndpoint(mgr *manager.Manager) string {
+	var containerRuntimeEndpoint string
 	switch mgr.Cluster.Kubernetes.ContainerManager {
 	case "docker":
 		containerRuntimeEndpoint = ""
@@ -212,31 +572,7 @@ func GenerateKubeadmCfg(mgr *manager.Manager) (string, error) {
 	if mgr.Cluster.Kubernetes.ContainerRuntimeEndpoint != "" {
 		containerRuntimeEndpoint = mgr.Cluster.Kubernetes.ContainerRuntimeEndpoint
 	}
-
-	cgroupDriver, err := getKubeletCgroupDriver(mgr)
-	if err != nil {
-		return "", err
-	}
-
-	return util.Render(KubeadmCfgTempl, util.Data{
-		"ImageRepo":            strings.TrimSuffix(preinstall.GetImage(mgr, "kube-apiserver").ImageRepo(), "/kube-apiserver"),
-		"CorednsRepo":          strings.TrimSuffix(preinstall.GetImage(mgr, "coredns").ImageRepo(), "/coredns"),
-		"CorednsTag":           preinstall.GetImage(mgr, "coredns").Tag,
-		"Version":              mgr.Cluster.Kubernetes.Version,
-		"ClusterName":          mgr.Cluster.Kubernetes.ClusterName,
-		"ControlPlaneEndpoint": fmt.Sprintf("%s:%d", mgr.Cluster.ControlPlaneEndpoint.Domain, mgr.Cluster.ControlPlaneEndpoint.Port),
-		"PodSubnet":            mgr.Cluster.Network.KubePodsCIDR,
-		"ServiceSubnet":        mgr.Cluster.Network.KubeServiceCIDR,
-		"CertSANs":             mgr.Cluster.GenerateCertSANs(),
-		"ExternalEtcd":         externalEtcd,
-		"ClusterIP":            "169.254.25.10",
-		"MasqueradeAll":        mgr.Cluster.Kubernetes.MasqueradeAll,
-		"NodeCidrMaskSize":     mgr.Cluster.Kubernetes.NodeCidrMaskSize,
-		"MaxPods":              mgr.Cluster.Kubernetes.MaxPods,
-		"ProxyMode":            mgr.Cluster.Kubernetes.ProxyMode,
-		"CriSock":              containerRuntimeEndpoint,
-		"CgroupDriver":         cgroupDriver,
-	})
+	return containerRuntimeEndpoint
 }
 
 func getKubeletCgroupDriver(mgr *manager.Manager) (string, error) {
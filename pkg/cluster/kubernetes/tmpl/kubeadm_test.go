@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tmpl
+
+import (
+	"reflect"
+	"testing"
+
+	versionutil "k8s.io/apimachinery/pkg/util/version"
+)
+
+func TestKubeadmAPIVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{version: "v1.17.9", want: KubeadmV1beta2},
+		{version: "v1.21.14", want: KubeadmV1beta2},
+		{version: "v1.22.0", want: KubeadmV1beta3},
+		{version: "v1.22.0-rc.0", want: KubeadmV1beta2},
+		{version: "v1.26.5", want: KubeadmV1beta3},
+		{version: "v1.30.14", want: KubeadmV1beta3},
+		{version: "v1.31.0", want: KubeadmV1beta4},
+		{version: "v1.33.1", want: KubeadmV1beta4},
+	}
+	for _, tt := range tests {
+		if got := KubeadmAPIVersion(tt.version); got != tt.want {
+			t.Errorf("KubeadmAPIVersion(%q) = %q, want %q", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestFeatureGatesOf(t *testing.T) {
+	tests := []struct {
+		version string
+		gates   map[string]bool
+		want    map[string]bool
+	}{
+		{
+			version: "v1.16.13",
+			gates:   defaultKubeletFeatureGates,
+			want: map[string]bool{
+				"CSINodeInfo":                    true,
+				"VolumeSnapshotDataSource":       true,
+				"ExpandCSIVolumes":               true,
+				"RotateKubeletClientCertificate": true,
+				"RotateKubeletServerCertificate": true,
+			},
+		},
+		{
+			version: "v1.17.0",
+			gates:   defaultKubeletFeatureGates,
+			want: map[string]bool{
+				"VolumeSnapshotDataSource":       true,
+				"ExpandCSIVolumes":               true,
+				"RotateKubeletClientCertificate": true,
+				"RotateKubeletServerCertificate": true,
+			},
+		},
+		{
+			version: "v1.19.8",
+			gates:   defaultKubeletFeatureGates,
+			want: map[string]bool{
+				"VolumeSnapshotDataSource":       true,
+				"ExpandCSIVolumes":               true,
+				"RotateKubeletServerCertificate": true,
+			},
+		},
+		{
+			version: "v1.23.17",
+			gates:   defaultFeatureGates,
+			want: map[string]bool{
+				"ExpandCSIVolumes":               true,
+				"RotateKubeletServerCertificate": true,
+			},
+		},
+		{
+			version: "v1.24.0",
+			gates:   defaultFeatureGates,
+			want: map[string]bool{
+				"RotateKubeletServerCertificate": true,
+			},
+		},
+		{
+			version: "v1.31.0",
+			gates:   defaultKubeletFeatureGates,
+			want: map[string]bool{
+				"RotateKubeletServerCertificate": true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		if got := featureGatesOf(tt.gates, versionutil.MustParseSemantic(tt.version)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("featureGatesOf(%s) = %v, want %v", tt.version, got, tt.want)
+		}
+	}
+}
@@ -53,7 +53,7 @@ EnvironmentFile=-/var/lib/kubelet/kubeadm-flags.env
 # This is a file that the user can use for overrides of the kubelet args as a last resort. Preferably, the user should use
 # the .NodeRegistration.KubeletExtraArgs object in the configuration files instead. KUBELET_EXTRA_ARGS should be sourced from this file.
 EnvironmentFile=-/etc/default/kubelet
-Environment="KUBELET_EXTRA_ARGS=--node-ip={{ .NodeIP }} --hostname-override={{ .Hostname }} {{ if .ContainerRuntime }}--network-plugin=cni{{ end }}"
+Environment="KUBELET_EXTRA_ARGS=--node-ip={{ .NodeIP }} --hostname-override={{ .Hostname }} {{ if .ContainerRuntime }}--network-plugin=cni{{ end }}{{ if .CloudProvider }} --cloud-provider={{ .CloudProvider }}{{ end }}"
 ExecStart=
 ExecStart=/usr/local/bin/kubelet $KUBELET_KUBECONFIG_ARGS $KUBELET_CONFIG_ARGS $KUBELET_KUBEADM_ARGS $KUBELET_EXTRA_ARGS
     `)))
@@ -69,8 +69,9 @@ func GenerateKubeletEnv(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg)
 	var containerRuntime string
 
 	return util.Render(KubeletEnvTempl, util.Data{
-		"NodeIP":           node.InternalAddress,
+		"NodeIP":           mgr.Cluster.NodeIP(node),
 		"Hostname":         node.Name,
 		"ContainerRuntime": containerRuntime,
+		"CloudProvider":    mgr.Cluster.Kubernetes.CloudProvider,
 	})
 }
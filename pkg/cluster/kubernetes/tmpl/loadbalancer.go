@@ -0,0 +1,286 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tmpl
+
+import (
+	"crypto/md5"
+	"fmt"
+	"text/template"
+
+	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+	"github.com/kubesphere/kubekey/pkg/cluster/preinstall"
+	"github.com/kubesphere/kubekey/pkg/util"
+	"github.com/kubesphere/kubekey/pkg/util/manager"
+	"github.com/lithammer/dedent"
+)
+
+const (
+	// HaproxyDir is the directory of the haproxy configuration on the nodes running haproxy.
+	HaproxyDir        = "/etc/kubekey/haproxy"
+	HaproxyCfgPath    = HaproxyDir + "/haproxy.cfg"
+	HaproxyHealthPort = 8081
+	// StaticPodDir is the directory of the manifests of static pods.
+	StaticPodDir = "/etc/kubernetes/manifests"
+
+	// KeepalivedCfgPath and HaproxyServiceCfgPath are the configurations of the packaged keepalived and haproxy on the load balancer hosts.
+	KeepalivedCfgPath     = "/etc/keepalived/keepalived.conf"
+	HaproxyServiceCfgPath = "/etc/haproxy/haproxy.cfg"
+	// KeepalivedVirtualRouterID identifies the VRRP instance of the VIP, the peers are unicast so it does not conflict with other clusters.
+	KeepalivedVirtualRouterID = 51
+)
+
+var (
+	// HaproxyCfgTempl defines the template of the haproxy configuration which proxies the requests to all the kube-apiservers.
+	HaproxyCfgTempl = template.Must(template.New("haproxyCfg").Parse(
+		dedent.Dedent(`global
+    maxconn                 4000
+    log                     127.0.0.1 local0
+
+defaults
+    mode                    http
+    log                     global
+    option                  httplog
+    option                  dontlognull
+    option                  http-server-close
+    option                  redispatch
+    retries                 5
+    timeout http-request    5m
+    timeout queue           5m
+    timeout connect         30s
+    timeout client          30s
+    timeout server          15m
+    timeout http-keep-alive 30s
+    timeout check           30s
+    maxconn                 4000
+
+frontend healthz
+  bind 127.0.0.1:{{ .HealthPort }}
+  mode http
+  monitor-uri /healthz
+
+frontend kube_api_frontend
+  bind {{ .BindAddress }}:{{ .Port }}
+  mode tcp
+  option tcplog
+  default_backend kube_api_backend
+
+backend kube_api_backend
+  mode tcp
+  balance leastconn
+  default-server inter 15s downinter 15s rise 2 fall 2 slowstart 60s maxconn 1000 maxqueue 256 weight 100
+  option httpchk GET /healthz
+  http-check expect status 200
+  {{- range .MasterNodes }}
+  server {{ .Name }} {{ .InternalAddress }}:{{ $.Port }} check check-ssl verify none
+  {{- end }}
+    `)))
+
+	// HaproxyManifestTempl defines the template of the static pod of haproxy.
+	// The checksum of the configuration makes kubelet recreate the pod when the configuration is changed.
+	HaproxyManifestTempl = template.Must(template.New("haproxyManifest").Parse(
+		dedent.Dedent(`apiVersion: v1
+kind: Pod
+metadata:
+  name: haproxy
+  namespace: kube-system
+  labels:
+    addonmanager.kubernetes.io/mode: Reconcile
+    k8s-app: kube-haproxy
+  annotations:
+    cfg-checksum: "{{ .Checksum }}"
+spec:
+  hostNetwork: true
+  dnsPolicy: ClusterFirstWithHostNet
+  nodeSelector:
+    kubernetes.io/os: linux
+  priorityClassName: system-node-critical
+  containers:
+  - name: haproxy
+    image: {{ .Image }}
+    imagePullPolicy: IfNotPresent
+    resources:
+      requests:
+        cpu: 25m
+        memory: 32M
+    livenessProbe:
+      httpGet:
+        host: 127.0.0.1
+        path: /healthz
+        port: {{ .HealthPort }}
+    readinessProbe:
+      httpGet:
+        host: 127.0.0.1
+        path: /healthz
+        port: {{ .HealthPort }}
+    volumeMounts:
+    - mountPath: /usr/local/etc/haproxy/
+      name: etc-haproxy
+      readOnly: true
+  volumes:
+  - name: etc-haproxy
+    hostPath:
+      path: {{ .HaproxyDir }}
+    `)))
+
+	// KeepalivedCfgTempl defines the template of the keepalived configuration, the VIP is moved to another load balancer when the local haproxy is unhealthy.
+	KeepalivedCfgTempl = template.Must(template.New("keepalivedCfg").Parse(
+		dedent.Dedent(`global_defs {
+  script_user root
+  enable_script_security
+}
+
+vrrp_script check_haproxy {
+  script "/usr/bin/curl -sf http://127.0.0.1:{{ .HealthPort }}/healthz"
+  interval 2
+  fall 2
+  rise 2
+}
+
+vrrp_instance kube_apiserver {
+  state BACKUP
+  interface {{ .Interface }}
+  virtual_router_id {{ .VirtualRouterID }}
+  priority {{ .Priority }}
+  advert_int 1
+  unicast_src_ip {{ .InternalAddress }}
+  unicast_peer {
+    {{- range .Peers }}
+    {{ . }}
+    {{- end }}
+  }
+  virtual_ipaddress {
+    {{ .VIP }}
+  }
+  track_script {
+    check_haproxy
+  }
+}
+    `)))
+
+	// KubeVipManifestTempl defines the template of the static pod of kube-vip, which holds the VIP by ARP on the leader master.
+	KubeVipManifestTempl = template.Must(template.New("kubeVipManifest").Parse(
+		dedent.Dedent(`apiVersion: v1
+kind: Pod
+metadata:
+  name: kube-vip
+  namespace: kube-system
+spec:
+  containers:
+  - name: kube-vip
+    image: {{ .Image }}
+    imagePullPolicy: IfNotPresent
+    args:
+    - manager
+    env:
+    - name: vip_arp
+      value: "true"
+    - name: vip_interface
+      value: {{ .Interface }}
+    - name: vip_address
+      value: {{ .Address }}
+    - name: address
+      value: {{ .Address }}
+    - name: vip_cidr
+      value: "32"
+    - name: port
+      value: "{{ .Port }}"
+    - name: cp_enable
+      value: "true"
+    - name: cp_namespace
+      value: kube-system
+    - name: svc_enable
+      value: "false"
+    - name: vip_leaderelection
+      value: "true"
+    - name: vip_leaseduration
+      value: "5"
+    - name: vip_renewdeadline
+      value: "3"
+    - name: vip_retryperiod
+      value: "1"
+    securityContext:
+      capabilities:
+        add:
+        - NET_ADMIN
+        - NET_RAW
+        - SYS_TIME
+    volumeMounts:
+    - mountPath: /etc/kubernetes/admin.conf
+      name: kubeconfig
+      readOnly: true
+  hostAliases:
+  - hostnames:
+    - kubernetes
+    ip: 127.0.0.1
+  hostNetwork: true
+  volumes:
+  - name: kubeconfig
+    hostPath:
+      path: {{ .KubeConfig }}
+    `)))
+)
+
+// GenerateHaproxyCfg is used to generate the haproxy configuration, the backends are all the masters.
+func GenerateHaproxyCfg(masterNodes []kubekeyapiv1alpha1.HostCfg, bindAddress string, port int) (string, error) {
+	return util.Render(HaproxyCfgTempl, util.Data{
+		"MasterNodes": masterNodes,
+		"BindAddress": bindAddress,
+		"Port":        port,
+		"HealthPort":  HaproxyHealthPort,
+	})
+}
+
+// GenerateHaproxyManifest is used to generate the static pod of haproxy for the given configuration.
+func GenerateHaproxyManifest(mgr *manager.Manager, haproxyCfg string) (string, error) {
+	return util.Render(HaproxyManifestTempl, util.Data{
+		"Image":      preinstall.GetImage(mgr, "haproxy").ImageName(),
+		"Checksum":   fmt.Sprintf("%x", md5.Sum([]byte(haproxyCfg))),
+		"HealthPort": HaproxyHealthPort,
+		"HaproxyDir": HaproxyDir,
+	})
+}
+
+// GenerateKubeVipManifest is used to generate the static pod of kube-vip, the VIP is bound to the given interface.
+// The kubeconfig on the host is mounted as the admin.conf of kube-vip.
+func GenerateKubeVipManifest(mgr *manager.Manager, iface, kubeConfig string) (string, error) {
+	return util.Render(KubeVipManifestTempl, util.Data{
+		"Image":      preinstall.GetImage(mgr, "kube-vip").ImageName(),
+		"Interface":  iface,
+		"Address":    mgr.Cluster.ControlPlaneEndpoint.Address,
+		"Port":       mgr.Cluster.ControlPlaneEndpoint.Port,
+		"KubeConfig": kubeConfig,
+	})
+}
+
+// GenerateKeepalivedCfg is used to generate the keepalived configuration of the given load balancer host, the first host has the highest priority.
+func GenerateKeepalivedCfg(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg, index int, iface string) (string, error) {
+	var peers []string
+	for _, host := range mgr.LoadBalancerNodes {
+		if host.Name != node.Name {
+			peers = append(peers, host.InternalAddress)
+		}
+	}
+	return util.Render(KeepalivedCfgTempl, util.Data{
+		"HealthPort":      HaproxyHealthPort,
+		"Interface":       iface,
+		"VirtualRouterID": KeepalivedVirtualRouterID,
+		"Priority":        100 - index,
+		"InternalAddress": node.InternalAddress,
+		"Peers":           peers,
+		"VIP":             mgr.Cluster.ControlPlaneEndpoint.Address,
+	})
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tmpl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+	"github.com/pkg/errors"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	strategicMergePatchType = "strategic"
+	mergePatchType          = "merge"
+	jsonPatchType           = "json"
+)
+
+// kubeadmCfgKinds are the kinds of the kubeadm configuration documents which can be patched, mapped to their apiVersion.
+// A missing kubeadm.k8s.io document is created with the apiVersion of the other kubeadm.k8s.io documents if any.
+var kubeadmCfgKinds = map[string]string{
+	"ClusterConfiguration":   KubeadmV1beta2,
+	"InitConfiguration":      KubeadmV1beta2,
+	"JoinConfiguration":      KubeadmV1beta2,
+	"KubeletConfiguration":   "kubelet.config.k8s.io/v1beta1",
+	"KubeProxyConfiguration": "kubeproxy.config.k8s.io/v1alpha1",
+}
+
+// HasKubeadmCfgPatches returns whether there are patches for the documents of the given kind.
+func HasKubeadmCfgPatches(patches []kubekeyapiv1alpha1.KubeadmConfigPatch, kind string) bool {
+	for _, patch := range patches {
+		if patch.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// PatchKubeadmCfg applies the patches to the documents of the kubeadm configuration.
+// The documents of the given kinds are created if they are patched but missing, so that InitConfiguration can be patched without a container runtime endpoint, for example.
+func PatchKubeadmCfg(cfg string, patches []kubekeyapiv1alpha1.KubeadmConfigPatch, kinds ...string) (string, error) {
+	if len(patches) == 0 {
+		return cfg, nil
+	}
+	for _, patch := range patches {
+		if _, ok := kubeadmCfgKinds[patch.Kind]; !ok {
+			return "", errors.New(fmt.Sprintf("Unsupported kind of kubeadm config patch: %s", patch.Kind))
+		}
+		if patch.Type != "" && patch.Type != strategicMergePatchType && patch.Type != mergePatchType && patch.Type != jsonPatchType {
+			return "", errors.New(fmt.Sprintf("Unsupported type of kubeadm config patch: %s", patch.Type))
+		}
+	}
+
+	var docs [][]byte
+	found := map[string]bool{}
+	kubeadmAPIVersion := KubeadmV1beta2
+	reader := k8syaml.NewYAMLReader(bufio.NewReader(strings.NewReader(cfg)))
+	for {
+		content, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", errors.Wrap(errors.WithStack(err), "Failed to read kubeadm config")
+		}
+		doc, err := yaml.YAMLToJSON(content)
+		if err != nil {
+			return "", errors.Wrap(errors.WithStack(err), "Failed to parse kubeadm config")
+		}
+		if string(doc) == "null" {
+			continue
+		}
+		docs = append(docs, doc)
+		found[kindOf(doc)] = true
+		if apiVersion := apiVersionOf(doc); strings.HasPrefix(apiVersion, "kubeadm.k8s.io/") {
+			kubeadmAPIVersion = apiVersion
+		}
+	}
+	for _, kind := range kinds {
+		if !found[kind] && HasKubeadmCfgPatches(patches, kind) {
+			apiVersion := kubeadmCfgKinds[kind]
+			if strings.HasPrefix(apiVersion, "kubeadm.k8s.io/") {
+				apiVersion = kubeadmAPIVersion
+			}
+			docs = append(docs, []byte(fmt.Sprintf(`{"apiVersion":%q,"kind":%q}`, apiVersion, kind)))
+		}
+	}
+
+	var buf bytes.Buffer
+	for _, doc := range docs {
+		kind := kindOf(doc)
+		for i, patch := range patches {
+			if patch.Kind != kind {
+				continue
+			}
+			patched, err := applyKubeadmCfgPatch(doc, patch)
+			if err != nil {
+				return "", errors.Wrap(err, fmt.Sprintf("Failed to apply kubeadm config patch %d to %s", i, kind))
+			}
+			doc = patched
+		}
+		content, err := yaml.JSONToYAML(doc)
+		if err != nil {
+			return "", errors.Wrap(errors.WithStack(err), "Failed to generate kubeadm config")
+		}
+		buf.WriteString("---\n")
+		buf.Write(content)
+	}
+	return buf.String(), nil
+}
+
+func applyKubeadmCfgPatch(doc []byte, patch kubekeyapiv1alpha1.KubeadmConfigPatch) ([]byte, error) {
+	patchJSON, err := yaml.YAMLToJSON([]byte(patch.Patch))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	switch patch.Type {
+	case jsonPatchType:
+		jsonPatch, err := jsonpatch.DecodePatch(patchJSON)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return jsonPatch.Apply(doc)
+	case mergePatchType:
+		return jsonpatch.MergePatch(doc, patchJSON)
+	default:
+		var docValue, patchValue interface{}
+		if err := json.Unmarshal(doc, &docValue); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if err := json.Unmarshal(patchJSON, &patchValue); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return json.Marshal(strategicMerge(docValue, patchValue))
+	}
+}
+
+// strategicMerge merges the patch into the value like a strategic merge patch of the kubeadm configuration.
+// The objects are merged recursively and a null deletes the field, the lists of objects with names, such as extraVolumes
+// and the extraArgs of v1beta4, are merged by the names, and the items missing from the lists of scalars, such as certSANs, are appended.
+// The other lists are replaced as a whole.
+func strategicMerge(value, patch interface{}) interface{} {
+	switch patch := patch.(type) {
+	case map[string]interface{}:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			object = map[string]interface{}{}
+		}
+		for k, v := range patch {
+			if v == nil {
+				delete(object, k)
+				continue
+			}
+			object[k] = strategicMerge(object[k], v)
+		}
+		return object
+	case []interface{}:
+		list, ok := value.([]interface{})
+		if !ok {
+			return patch
+		}
+		switch {
+		case namedItems(list) && namedItems(patch):
+			merged := append([]interface{}{}, list...)
+			for _, item := range patch {
+				name := item.(map[string]interface{})["name"]
+				found := false
+				for i := range merged {
+					if merged[i].(map[string]interface{})["name"] == name {
+						merged[i] = strategicMerge(merged[i], item)
+						found = true
+						break
+					}
+				}
+				if !found {
+					merged = append(merged, item)
+				}
+			}
+			return merged
+		case scalarItems(list) && scalarItems(patch):
+			merged := append([]interface{}{}, list...)
+			for _, item := range patch {
+				found := false
+				for _, existing := range merged {
+					if existing == item {
+						found = true
+						break
+					}
+				}
+				if !found {
+					merged = append(merged, item)
+				}
+			}
+			return merged
+		default:
+			return patch
+		}
+	default:
+		return patch
+	}
+}
+
+// namedItems returns whether all the items of the list are objects with a name.
+func namedItems(list []interface{}) bool {
+	for _, item := range list {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if _, ok := object["name"].(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// scalarItems returns whether all the items of the list are neither objects nor lists.
+func scalarItems(list []interface{}) bool {
+	for _, item := range list {
+		switch item.(type) {
+		case map[string]interface{}, []interface{}:
+			return false
+		}
+	}
+	return true
+}
+
+func kindOf(doc []byte) string {
+	meta := struct {
+		Kind string `json:"kind"`
+	}{}
+	_ = yaml.Unmarshal(doc, &meta)
+	return meta.Kind
+}
+
+func apiVersionOf(doc []byte) string {
+	meta := struct {
+		APIVersion string `json:"apiVersion"`
+	}{}
+	_ = yaml.Unmarshal(doc, &meta)
+	return meta.APIVersion
+}
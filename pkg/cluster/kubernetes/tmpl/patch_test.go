@@ -0,0 +1,247 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tmpl
+
+import (
+	"testing"
+
+	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+)
+
+const testKubeadmCfg = `---
+apiVersion: kubeadm.k8s.io/v1beta3
+kind: ClusterConfiguration
+apiServer:
+  certSANs:
+  - lb.kubesphere.local
+  - 127.0.0.1
+  extraArgs:
+    bind-address: 0.0.0.0
+    audit-log-maxage: "30"
+  extraVolumes:
+  - name: audit
+    hostPath: /etc/kubernetes/audit
+    mountPath: /etc/kubernetes/audit
+    readOnly: true
+---
+apiVersion: kubelet.config.k8s.io/v1beta1
+kind: KubeletConfiguration
+maxPods: 110
+`
+
+func TestPatchKubeadmCfg(t *testing.T) {
+	tests := []struct {
+		name    string
+		patches []kubekeyapiv1alpha1.KubeadmConfigPatch
+		kinds   []string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "no patches",
+			want: testKubeadmCfg,
+		},
+		{
+			name: "strategic merge by names and of scalars",
+			patches: []kubekeyapiv1alpha1.KubeadmConfigPatch{{
+				Kind: "ClusterConfiguration",
+				Patch: `apiServer:
+  certSANs:
+  - 127.0.0.1
+  - 10.0.0.1
+  extraArgs:
+    audit-log-maxage: null
+    v: "4"
+  extraVolumes:
+  - name: audit
+    readOnly: false
+  - name: encryption
+    hostPath: /etc/kubernetes/encryption
+    mountPath: /etc/kubernetes/encryption
+`,
+			}},
+			want: `---
+apiServer:
+  certSANs:
+  - lb.kubesphere.local
+  - 127.0.0.1
+  - 10.0.0.1
+  extraArgs:
+    bind-address: 0.0.0.0
+    v: "4"
+  extraVolumes:
+  - hostPath: /etc/kubernetes/audit
+    mountPath: /etc/kubernetes/audit
+    name: audit
+    readOnly: false
+  - hostPath: /etc/kubernetes/encryption
+    mountPath: /etc/kubernetes/encryption
+    name: encryption
+apiVersion: kubeadm.k8s.io/v1beta3
+kind: ClusterConfiguration
+---
+apiVersion: kubelet.config.k8s.io/v1beta1
+kind: KubeletConfiguration
+maxPods: 110
+`,
+		},
+		{
+			name: "merge replaces lists",
+			patches: []kubekeyapiv1alpha1.KubeadmConfigPatch{{
+				Kind: "ClusterConfiguration",
+				Type: "merge",
+				Patch: `apiServer:
+  certSANs:
+  - 10.0.0.1
+  extraVolumes: null
+`,
+			}},
+			want: `---
+apiServer:
+  certSANs:
+  - 10.0.0.1
+  extraArgs:
+    audit-log-maxage: "30"
+    bind-address: 0.0.0.0
+apiVersion: kubeadm.k8s.io/v1beta3
+kind: ClusterConfiguration
+---
+apiVersion: kubelet.config.k8s.io/v1beta1
+kind: KubeletConfiguration
+maxPods: 110
+`,
+		},
+		{
+			name: "json patch",
+			patches: []kubekeyapiv1alpha1.KubeadmConfigPatch{{
+				Kind:  "KubeletConfiguration",
+				Type:  "json",
+				Patch: `[{"op": "replace", "path": "/maxPods", "value": 200}, {"op": "add", "path": "/serializeImagePulls", "value": false}]`,
+			}},
+			want: `---
+apiServer:
+  certSANs:
+  - lb.kubesphere.local
+  - 127.0.0.1
+  extraArgs:
+    audit-log-maxage: "30"
+    bind-address: 0.0.0.0
+  extraVolumes:
+  - hostPath: /etc/kubernetes/audit
+    mountPath: /etc/kubernetes/audit
+    name: audit
+    readOnly: true
+apiVersion: kubeadm.k8s.io/v1beta3
+kind: ClusterConfiguration
+---
+apiVersion: kubelet.config.k8s.io/v1beta1
+kind: KubeletConfiguration
+maxPods: 200
+serializeImagePulls: false
+`,
+		},
+		{
+			name: "missing document created with the kubeadm apiVersion",
+			patches: []kubekeyapiv1alpha1.KubeadmConfigPatch{{
+				Kind: "InitConfiguration",
+				Patch: `nodeRegistration:
+  criSocket: unix:///run/containerd/containerd.sock
+`,
+			}},
+			kinds: []string{"InitConfiguration"},
+			want: `---
+apiServer:
+  certSANs:
+  - lb.kubesphere.local
+  - 127.0.0.1
+  extraArgs:
+    audit-log-maxage: "30"
+    bind-address: 0.0.0.0
+  extraVolumes:
+  - hostPath: /etc/kubernetes/audit
+    mountPath: /etc/kubernetes/audit
+    name: audit
+    readOnly: true
+apiVersion: kubeadm.k8s.io/v1beta3
+kind: ClusterConfiguration
+---
+apiVersion: kubelet.config.k8s.io/v1beta1
+kind: KubeletConfiguration
+maxPods: 110
+---
+apiVersion: kubeadm.k8s.io/v1beta3
+kind: InitConfiguration
+nodeRegistration:
+  criSocket: unix:///run/containerd/containerd.sock
+`,
+		},
+		{
+			name: "missing document of other kinds not created",
+			patches: []kubekeyapiv1alpha1.KubeadmConfigPatch{{
+				Kind:  "JoinConfiguration",
+				Patch: `caCertPath: /etc/kubernetes/pki/ca.crt`,
+			}},
+			kinds: []string{"InitConfiguration"},
+			want: `---
+apiServer:
+  certSANs:
+  - lb.kubesphere.local
+  - 127.0.0.1
+  extraArgs:
+    audit-log-maxage: "30"
+    bind-address: 0.0.0.0
+  extraVolumes:
+  - hostPath: /etc/kubernetes/audit
+    mountPath: /etc/kubernetes/audit
+    name: audit
+    readOnly: true
+apiVersion: kubeadm.k8s.io/v1beta3
+kind: ClusterConfiguration
+---
+apiVersion: kubelet.config.k8s.io/v1beta1
+kind: KubeletConfiguration
+maxPods: 110
+`,
+		},
+		{
+			name:    "unsupported kind",
+			patches: []kubekeyapiv1alpha1.KubeadmConfigPatch{{Kind: "Pod", Patch: `spec: {}`}},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported type",
+			patches: []kubekeyapiv1alpha1.KubeadmConfigPatch{{Kind: "ClusterConfiguration", Type: "replace", Patch: `apiServer: {}`}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid json patch",
+			patches: []kubekeyapiv1alpha1.KubeadmConfigPatch{{Kind: "ClusterConfiguration", Type: "json", Patch: `apiServer: {}`}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PatchKubeadmCfg(testKubeadmCfg, tt.patches, tt.kinds...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PatchKubeadmCfg() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("PatchKubeadmCfg() got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
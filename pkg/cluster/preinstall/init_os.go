@@ -94,6 +94,26 @@ func initOsOnNode(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) error
 	return nil
 }
 
+// RefreshHosts is used to rewrite the hosts of the cluster in /etc/hosts, such as after a node is deleted.
+func RefreshHosts(mgr *manager.Manager) error {
+	mgr.Logger.Infoln("Refreshing hosts ...")
+
+	return mgr.RunTaskOnAllNodes(refreshHosts, true)
+}
+
+func refreshHosts(mgr *manager.Manager, _ *kubekeyapiv1alpha1.HostCfg) error {
+	refreshHostsScript, err := tmpl.RefreshHostsScript(mgr)
+	if err != nil {
+		return err
+	}
+
+	str := base64.StdEncoding.EncodeToString([]byte(refreshHostsScript))
+	if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"echo %s | base64 -d > %s/refreshHosts.sh && chmod +x %s/refreshHosts.sh && %s/refreshHosts.sh\"", str, kubeScriptDir, kubeScriptDir, kubeScriptDir), 1, false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "Failed to refresh hosts")
+	}
+	return nil
+}
+
 func addUsers(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) error {
 	if _, err := mgr.Runner.ExecuteCmd("sudo -E /bin/sh -c \"useradd -M -c 'Kubernetes user' -s /sbin/nologin -r kube || :\"", 1, false); err != nil {
 		return err
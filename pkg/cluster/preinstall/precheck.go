@@ -40,7 +40,7 @@ var (
 // Precheck is used to perform the check function.
 func Precheck(mgr *manager.Manager) error {
 	//Check that the number of Etcd is odd
-	if len(mgr.EtcdNodes)%2 == 0 {
+	if mgr.Cluster.Etcd.Type != kubekeyapiv1alpha1.EtcdTypeExternal && len(mgr.EtcdNodes)%2 == 0 {
 		mgr.Logger.Warnln("The number of etcd is even. Please configure it to be odd.")
 		return errors.New("the number of etcd is even")
 	}
@@ -48,12 +48,18 @@ echo 'net.bridge.bridge-nf-call-arptables = 1' >> /etc/sysctl.conf
 echo 'net.bridge.bridge-nf-call-ip6tables = 1' >> /etc/sysctl.conf
 echo 'net.bridge.bridge-nf-call-iptables = 1' >> /etc/sysctl.conf
 echo 'net.ipv4.ip_local_reserved_ports = 30000-32767' >> /etc/sysctl.conf
+{{- if .DualStack }}
+echo 'net.ipv6.conf.all.forwarding = 1' >> /etc/sysctl.conf
+{{- end }}
 
 sed -r -i  "s@#{0,}?net.ipv4.ip_forward ?= ?(0|1)@net.ipv4.ip_forward = 1@g" /etc/sysctl.conf
 sed -r -i  "s@#{0,}?net.bridge.bridge-nf-call-arptables ?= ?(0|1)@net.bridge.bridge-nf-call-arptables = 1@g" /etc/sysctl.conf
 sed -r -i  "s@#{0,}?net.bridge.bridge-nf-call-ip6tables ?= ?(0|1)@net.bridge.bridge-nf-call-ip6tables = 1@g" /etc/sysctl.conf
 sed -r -i  "s@#{0,}?net.bridge.bridge-nf-call-iptables ?= ?(0|1)@net.bridge.bridge-nf-call-iptables = 1@g" /etc/sysctl.conf
 sed -r -i  "s@#{0,}?net.ipv4.ip_local_reserved_ports ?= ?(0|1)@net.ipv4.ip_local_reserved_ports = 30000-32767@g" /etc/sysctl.conf
+{{- if .DualStack }}
+sed -r -i  "s@#{0,}?net.ipv6.conf.all.forwarding ?= ?(0|1)@net.ipv6.conf.all.forwarding = 1@g" /etc/sysctl.conf
+{{- end }}
 
 awk ' !x[$0]++{print > "/etc/sysctl.conf"}' /etc/sysctl.conf
 
@@ -124,8 +130,31 @@ crontab /tmp/file1
 rm -rf /tmp/file1
     `)))
 
+var refreshHostsScriptTmpl = template.Must(template.New("refreshHosts").Parse(
+	dedent.Dedent(`#!/usr/bin/env bash
+
+sed -i ':a;$!{N;ba};s@# kubekey hosts BEGIN.*# kubekey hosts END@@' /etc/hosts
+sed -i '/^$/N;/\n$/N;//D' /etc/hosts
+
+cat >>/etc/hosts<<EOF
+# kubekey hosts BEGIN
+{{- range .Hosts }}
+{{ . }}
+{{- end }}
+# kubekey hosts END
+EOF
+    `)))
+
 func InitOsScript(mgr *manager.Manager) (string, error) {
 	return util.Render(initOsScriptTmpl, util.Data{
+		"Hosts":     mgr.ClusterHosts,
+		"DualStack": mgr.Cluster.Network.DualStack(),
+	})
+}
+
+// RefreshHostsScript renders the script which rewrites the hosts of the cluster in /etc/hosts.
+func RefreshHostsScript(mgr *manager.Manager) (string, error) {
+	return util.Render(refreshHostsScriptTmpl, util.Data{
 		"Hosts": mgr.ClusterHosts,
 	})
 }
@@ -21,37 +21,44 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"io/ioutil"
 	"net"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"text/template"
 
-	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha2"
 	"github.com/kubesphere/kubekey/pkg/util"
 	"github.com/lithammer/dedent"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 var (
 	// ClusterCfgTempl defines the template of cluster configuration file for the existing cluster.
 	ClusterCfgTempl = template.Must(template.New("ClusterCfg").Parse(
-		dedent.Dedent(`apiVersion: kubekey.kubesphere.io/v1alpha1
+		dedent.Dedent(`apiVersion: kubekey.kubesphere.io/v1alpha2
 kind: Cluster
 metadata:
   name: {{ .Options.Name }}
 spec:
-  hosts: 
+  hosts:
+  {{- if not .Options.CredentialsProvided }}
   # You should complete the ssh information of the hosts
-  {{- range .Options.Hosts }}
-  - {{ . }}
   {{- end }}
+{{ .Options.Hosts }}
   roleGroups:
     etcd:
-    - SHOULD_BE_REPLACED
+    {{- range .Options.EtcdGroup }}
+    - {{ . }}
+    {{- end }}
     master: 
     {{- range .Options.MasterGroup }}
     - {{ . }}
@@ -65,6 +72,10 @@ spec:
     domain: {{ .Options.ControlPlaneEndpointDomain }}
     address: {{ .Options.ControlPlaneEndpointAddress }}
     port: {{ .Options.ControlPlaneEndpointPort }}
+  {{- if .Options.EtcdType }}
+  etcd:
+    type: {{ .Options.EtcdType }}
+  {{- end }}
   kubernetes:
     version: {{ .Options.KubeVersion }}
     clusterName: {{ .Options.ClusterName }}
@@ -72,12 +83,31 @@ spec:
     masqueradeAll: {{ .Options.MasqueradeAll }}
     maxPods: {{ .Options.MaxPods }}
     nodeCidrMaskSize: {{ .Options.NodeCidrMaskSize }}
+    {{- if or .Options.IPVSStrictARP (and .Options.IPVSScheduler (ne .Options.IPVSScheduler "rr")) }}
+    kubeProxy:
+      ipvs:
+        {{- if .Options.IPVSScheduler }}
+        scheduler: {{ .Options.IPVSScheduler }}
+        {{- end }}
+        strictARP: {{ .Options.IPVSStrictARP }}
+    {{- end }}
+    {{- if .Options.ContainerManager }}
+    containerManager: {{ .Options.ContainerManager }}
+    {{- end }}
   network:
     plugin: {{ .Options.NetworkPlugin }}
     kubePodsCIDR: {{ .Options.PodNetworkCidr }}
     kubeServiceCIDR: {{ .Options.ServiceNetworkCidr }}
+    {{- if eq .Options.NetworkPlugin "calico" }}
+    calico:
+      ipipMode: {{ .Options.CalicoIPIPMode }}
+      vxlanMode: {{ .Options.CalicoVXLANMode }}
+      {{- if .Options.CalicoVethMTU }}
+      vethMTU: {{ .Options.CalicoVethMTU }}
+      {{- end }}
+    {{- end }}
   registry:
-    privateRegistry: ""
+    privateRegistry: "{{ .Options.PrivateRegistry }}"
 
     `)))
 )
@@ -92,26 +122,91 @@ func GenerateClusterCfgStr(opt *OptionsCluster) (string, error) {
 // OptionsCluster defineds the parameters of cluster configuration for the existing cluster.
 type OptionsCluster struct {
 	Name                        string
-	Hosts                       []string
+	Hosts                       string
+	CredentialsProvided         bool
+	EtcdGroup                   []string
 	MasterGroup                 []string
 	WorkerGroup                 []string
 	KubeVersion                 string
 	ImageRepo                   string
+	PrivateRegistry             string
 	ClusterName                 string
 	MasqueradeAll               string
 	ProxyMode                   string
+	IPVSScheduler               string
+	IPVSStrictARP               bool
 	MaxPods                     string
 	NodeCidrMaskSize            string
+	ContainerManager            string
 	PodNetworkCidr              string
 	ServiceNetworkCidr          string
 	NetworkPlugin               string
+	CalicoIPIPMode              string
+	CalicoVXLANMode             string
+	CalicoVethMTU               string
 	ControlPlaneEndpointDomain  string
 	ControlPlaneEndpointAddress string
 	ControlPlaneEndpointPort    string
+	EtcdType                    string
+}
+
+// HostCredentials defines the ssh information of the hosts, which can not be fetched from the existing cluster.
+type HostCredentials struct {
+	// Default is applied to all hosts.
+	Default HostCredential `yaml:"default"`
+	// Hosts are keyed by the node name or the internal address, and take precedence over Default.
+	Hosts map[string]HostCredential `yaml:"hosts"`
+}
+
+// HostCredential defines how to connect to a host by ssh.
+type HostCredential struct {
+	Address        string `yaml:"address"`
+	Port           int    `yaml:"port"`
+	User           string `yaml:"user"`
+	Password       string `yaml:"password"`
+	PrivateKey     string `yaml:"privateKey"`
+	PrivateKeyPath string `yaml:"privateKeyPath"`
+}
+
+// LoadHostCredentials is used to read the host credentials file.
+func LoadHostCredentials(path string) (*HostCredentials, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read the host credentials file")
+	}
+	credentials := HostCredentials{}
+	if err := yaml.UnmarshalStrict(content, &credentials); err != nil {
+		return nil, errors.Wrap(err, "Failed to parse the host credentials file")
+	}
+	return &credentials, nil
+}
+
+func (c *HostCredentials) apply(host *kubekeyapiv1alpha2.HostCfg) {
+	for _, credential := range []HostCredential{c.Default, c.Hosts[host.Name], c.Hosts[host.InternalAddress]} {
+		if credential.Address != "" {
+			host.Address = credential.Address
+		}
+		if credential.Port != 0 {
+			host.Port = credential.Port
+		}
+		if credential.User != "" {
+			host.User = credential.User
+		}
+		if credential.Password != "" {
+			host.Password = credential.Password
+		}
+		if credential.PrivateKey != "" {
+			host.PrivateKey = credential.PrivateKey
+		}
+		if credential.PrivateKeyPath != "" {
+			host.PrivateKeyPath = credential.PrivateKeyPath
+		}
+	}
 }
 
 // GetInfoFromCluster is used to fetch information from the existing cluster.
-func GetInfoFromCluster(config, name string) (*OptionsCluster, error) {
+// The ssh information of hosts is taken from the given credentials, which is optional.
+func GetInfoFromCluster(config, name string, credentials *HostCredentials) (*OptionsCluster, error) {
 	clientset, err := util.NewClient(config)
 	if err != nil {
 		return nil, err
@@ -130,33 +225,49 @@ func GetInfoFromCluster(config, name string) (*OptionsCluster, error) {
 		opt.Name = "config-sample"
 	}
 
+	var hosts []kubekeyapiv1alpha2.HostCfg
+	hostNames := map[string]string{}
 	for _, node := range nodes.Items {
-		nodeCfg := kubekeyapiv1alpha1.HostCfg{}
-		nodeInfo, err := clientset.CoreV1().Nodes().Get(context.TODO(), node.Name, metav1.GetOptions{})
-		if err != nil {
-			return nil, err
+		nodeCfg := kubekeyapiv1alpha2.HostCfg{
+			Arch:   node.Status.NodeInfo.Architecture,
+			Labels: userLabels(node.Labels),
+			Taints: userTaints(node.Spec.Taints),
 		}
-		for _, address := range nodeInfo.Status.Addresses {
+		for _, address := range node.Status.Addresses {
 			if address.Type == "Hostname" {
 				nodeCfg.Name = address.Address
 			}
 			if address.Type == "InternalIP" {
-				nodeCfg.Address = address.Address
-				nodeCfg.InternalAddress = address.Address
+				// a node of a dual-stack cluster has both an IPv4 and an IPv6 internal IP
+				if ip := net.ParseIP(address.Address); ip != nil && ip.To4() == nil {
+					nodeCfg.InternalIPv6 = address.Address
+				} else {
+					nodeCfg.Address = address.Address
+					nodeCfg.InternalAddress = address.Address
+				}
 			}
 		}
-		if _, ok := nodeInfo.Labels["node-role.kubernetes.io/master"]; ok {
+		if nodeCfg.InternalAddress == "" {
+			nodeCfg.Address, nodeCfg.InternalAddress, nodeCfg.InternalIPv6 = nodeCfg.InternalIPv6, nodeCfg.InternalIPv6, ""
+		}
+		// the masters are labeled node-role.kubernetes.io/control-plane since v1.20, and the master label is removed since v1.24
+		_, master := node.Labels["node-role.kubernetes.io/master"]
+		_, controlPlane := node.Labels["node-role.kubernetes.io/control-plane"]
+		if master || controlPlane {
 			opt.MasterGroup = append(opt.MasterGroup, nodeCfg.Name)
-			if _, ok := nodeInfo.Labels["node-role.kubernetes.io/worker"]; ok {
+			if _, ok := node.Labels["node-role.kubernetes.io/worker"]; ok {
 				opt.WorkerGroup = append(opt.WorkerGroup, nodeCfg.Name)
 			}
 		} else {
 			opt.WorkerGroup = append(opt.WorkerGroup, nodeCfg.Name)
 		}
-		nodeCfgStr := fmt.Sprintf("{name: %s, address: %s, internalAddress: %s}", nodeCfg.Name, nodeCfg.Address, nodeCfg.InternalAddress)
-		opt.Hosts = append(opt.Hosts, nodeCfgStr)
+		hosts = append(hosts, nodeCfg)
+		hostNames[nodeCfg.InternalAddress] = nodeCfg.Name
 
-		opt.MaxPods = nodeInfo.Status.Capacity.Pods().String()
+		opt.MaxPods = node.Status.Capacity.Pods().String()
+		if opt.ContainerManager == "" {
+			opt.ContainerManager = containerManager(node.Status.NodeInfo.ContainerRuntimeVersion)
+		}
 	}
 
 	kubeadmConfig, err := clientset.CoreV1().ConfigMaps("kube-system").Get(context.TODO(), "kubeadm-config", metav1.GetOptions{})
@@ -170,14 +281,20 @@ func GetInfoFromCluster(config, name string) (*OptionsCluster, error) {
 	}
 	opt.KubeVersion = viper.GetString("kubernetesVersion")
 	opt.ImageRepo = viper.GetString("imageRepository")
+	opt.PrivateRegistry = privateRegistry(opt.ImageRepo)
 	opt.ClusterName = viper.GetString("clusterName")
 	opt.PodNetworkCidr = viper.GetString("networking.podSubnet")
 	opt.ServiceNetworkCidr = viper.GetString("networking.serviceSubnet")
-	if viper.IsSet("controllerManager.extraArgs.node-cidr-mask-size") {
+	if viper.IsSet("controllerManager.extraArgs.node-cidr-mask-size-ipv4") {
+		opt.NodeCidrMaskSize = viper.GetString("controllerManager.extraArgs.node-cidr-mask-size-ipv4")
+	} else if viper.IsSet("controllerManager.extraArgs.node-cidr-mask-size") {
 		opt.NodeCidrMaskSize = viper.GetString("controllerManager.extraArgs.node-cidr-mask-size")
 	} else {
 		opt.NodeCidrMaskSize = "24"
 	}
+	opt.ControlPlaneEndpointDomain = kubekeyapiv1alpha2.DefaultLBDomain
+	opt.ControlPlaneEndpointAddress = "\"\""
+	opt.ControlPlaneEndpointPort = fmt.Sprintf("%d", kubekeyapiv1alpha2.DefaultLBPort)
 	if viper.IsSet("controlPlaneEndpoint") {
 		controlPlaneEndpointStr := viper.GetString("controlPlaneEndpoint")
 		strList := strings.Split(controlPlaneEndpointStr, ":")
@@ -186,26 +303,61 @@ func GetInfoFromCluster(config, name string) (*OptionsCluster, error) {
 		address := strings.Join(strList, ":")
 		ip := net.ParseIP(address)
 		if ip != nil {
-			opt.ControlPlaneEndpointAddress = address
-			opt.ControlPlaneEndpointDomain = "lb.kubesphere.local"
+			// the address of load balancer is not required when there is only one master.
+			if len(opt.MasterGroup) > 1 {
+				opt.ControlPlaneEndpointAddress = address
+			}
 		} else {
-			opt.ControlPlaneEndpointAddress = "\"\""
 			opt.ControlPlaneEndpointDomain = address
 		}
 	}
 
-	pods, err := clientset.CoreV1().Pods("kube-system").List(context.TODO(), metav1.ListOptions{})
-	if err != nil {
-		return nil, err
+	etcdEndpoints := viper.GetStringSlice("etcd.external.endpoints")
+	if len(etcdEndpoints) == 0 && viper.IsSet("etcd.local") {
+		// the etcd members are stacked on the masters
+		opt.EtcdGroup = append(opt.EtcdGroup, opt.MasterGroup...)
+		opt.EtcdType = kubekeyapiv1alpha2.EtcdTypeKubeadm
+	} else if len(etcdEndpoints) == 0 {
+		if etcdEndpoints, err = etcdServersOfApiserver(clientset); err != nil {
+			return nil, err
+		}
 	}
-	for _, pod := range pods.Items {
-		if strings.Contains(pod.Name, "calico") {
-			opt.NetworkPlugin = "calico"
+	for _, endpoint := range etcdEndpoints {
+		u, err := url.Parse(strings.TrimSpace(endpoint))
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("Failed to parse the etcd endpoint %s", endpoint))
 		}
-		if strings.Contains(pod.Name, "flannel") {
-			opt.NetworkPlugin = "flannel"
+		address := u.Hostname()
+		if ip := net.ParseIP(address); address == "localhost" || (ip != nil && ip.IsLoopback()) {
+			// each kube-apiserver accesses the etcd member on its own master
+			if len(opt.MasterGroup) == 0 {
+				return nil, errors.New(fmt.Sprintf("Failed to find the masters running the local etcd endpoint %s", endpoint))
+			}
+			for _, master := range opt.MasterGroup {
+				if !containsString(opt.EtcdGroup, master) {
+					opt.EtcdGroup = append(opt.EtcdGroup, master)
+				}
+			}
+			continue
+		}
+		hostName, ok := hostNames[address]
+		if !ok {
+			// the etcd member is not a node of the cluster
+			hostName = fmt.Sprintf("etcd-%s", strings.NewReplacer(".", "-", ":", "-").Replace(address))
+			hosts = append(hosts, kubekeyapiv1alpha2.HostCfg{Name: hostName, Address: address, InternalAddress: address})
+			hostNames[address] = hostName
+		}
+		if !containsString(opt.EtcdGroup, hostName) {
+			opt.EtcdGroup = append(opt.EtcdGroup, hostName)
 		}
 	}
+	if len(opt.EtcdGroup) == 0 {
+		return nil, errors.New("Failed to find the etcd members of the cluster")
+	}
+
+	if err := getNetworkInfo(clientset, &opt); err != nil {
+		return nil, err
+	}
 
 	kubeProxyConfig, err := clientset.CoreV1().ConfigMaps("kube-system").Get(context.TODO(), "kube-proxy", metav1.GetOptions{})
 	if err != nil {
@@ -220,16 +372,222 @@ func GetInfoFromCluster(config, name string) (*OptionsCluster, error) {
 	opt.MasqueradeAll = viper.GetString("iptables.masqueradeAll")
 	if viper.GetString("mode") == "ipvs" {
 		opt.ProxyMode = viper.GetString("mode")
+		// keep the ipvs options of the cluster, or they are reset when kube-proxy is configured by kk
+		opt.IPVSScheduler = viper.GetString("ipvs.scheduler")
+		opt.IPVSStrictARP = viper.GetBool("ipvs.strictARP")
 	} else {
 		opt.ProxyMode = "iptables"
 	}
 
+	if credentials != nil {
+		for i := range hosts {
+			credentials.apply(&hosts[i])
+		}
+		opt.CredentialsProvided = true
+	}
+	if opt.Hosts, err = hostsYaml(hosts); err != nil {
+		return nil, err
+	}
+
 	return &opt, nil
 }
 
+// etcdServersOfApiserver is used to find the etcd endpoints from the flags of kube-apiserver.
+func etcdServersOfApiserver(clientset *kubernetes.Clientset) ([]string, error) {
+	pods, err := clientset.CoreV1().Pods("kube-system").List(context.TODO(), metav1.ListOptions{LabelSelector: "component=kube-apiserver"})
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range pods.Items {
+		for _, container := range pod.Spec.Containers {
+			for _, arg := range append(container.Command, container.Args...) {
+				if strings.HasPrefix(arg, "--etcd-servers=") {
+					return strings.Split(strings.TrimPrefix(arg, "--etcd-servers="), ","), nil
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// getNetworkInfo is used to detect the network plugin by the daemonsets in kube-system.
+func getNetworkInfo(clientset *kubernetes.Clientset, opt *OptionsCluster) error {
+	daemonSets, err := clientset.AppsV1().DaemonSets("kube-system").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	// the network plugin is not managed if it can not be recognized
+	opt.NetworkPlugin = "none"
+	for _, ds := range daemonSets.Items {
+		switch {
+		case strings.Contains(ds.Name, "calico-node"):
+			opt.NetworkPlugin = "calico"
+			opt.CalicoIPIPMode = kubekeyapiv1alpha2.DefaultIPIPMode
+			opt.CalicoVXLANMode = kubekeyapiv1alpha2.DefaultVXLANMode
+			for _, container := range ds.Spec.Template.Spec.Containers {
+				for _, env := range container.Env {
+					switch env.Name {
+					case "CALICO_IPV4POOL_IPIP":
+						opt.CalicoIPIPMode = calicoEncapsulationMode(env.Value)
+					case "CALICO_IPV4POOL_VXLAN":
+						opt.CalicoVXLANMode = calicoEncapsulationMode(env.Value)
+					}
+				}
+			}
+			if calicoConfig, err := clientset.CoreV1().ConfigMaps("kube-system").Get(context.TODO(), "calico-config", metav1.GetOptions{}); err == nil {
+				opt.CalicoVethMTU = calicoConfig.Data["veth_mtu"]
+			}
+		case strings.Contains(ds.Name, "flannel"):
+			opt.NetworkPlugin = "flannel"
+		case strings.Contains(ds.Name, "cilium"):
+			opt.NetworkPlugin = "cilium"
+		case strings.Contains(ds.Name, "kube-ovn-cni"):
+			opt.NetworkPlugin = "kubeovn"
+		}
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func calicoEncapsulationMode(mode string) string {
+	if mode == "Off" || mode == "" {
+		return "Never"
+	}
+	return mode
+}
+
+// containerManager returns the container manager by the runtime version of node, e.g. docker://19.3.8.
+func containerManager(runtimeVersion string) string {
+	switch strings.Split(runtimeVersion, "://")[0] {
+	case "docker":
+		return "docker"
+	case "containerd":
+		return "containerd"
+	case "cri-o":
+		return "crio"
+	case "isulad":
+		return "isula"
+	default:
+		return ""
+	}
+}
+
+// privateRegistry returns the private registry by the image repository of kubeadm, which is <registry>/kubesphere when a private registry is used.
+func privateRegistry(imageRepo string) string {
+	suffix := "/" + kubekeyapiv1alpha2.DefaultKubeImageNamespace
+	if strings.HasSuffix(imageRepo, suffix) {
+		return strings.TrimSuffix(imageRepo, suffix)
+	}
+	return ""
+}
+
+// isReservedKey checks whether the key of label or taint belongs to the namespaces reserved for kubernetes components.
+func isReservedKey(key string) bool {
+	if !strings.Contains(key, "/") {
+		return false
+	}
+	prefix := strings.Split(key, "/")[0]
+	return prefix == "kubernetes.io" || strings.HasSuffix(prefix, ".kubernetes.io") ||
+		prefix == "k8s.io" || strings.HasSuffix(prefix, ".k8s.io")
+}
+
+func userLabels(labels map[string]string) map[string]string {
+	result := map[string]string{}
+	for k, v := range labels {
+		if !isReservedKey(k) {
+			result[k] = v
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+func userTaints(taints []corev1.Taint) []corev1.Taint {
+	var result []corev1.Taint
+	for _, taint := range taints {
+		if !isReservedKey(taint.Key) {
+			result = append(result, corev1.Taint{Key: taint.Key, Value: taint.Value, Effect: taint.Effect})
+		}
+	}
+	return result
+}
+
+// hostsYaml renders the hosts as the items of spec.hosts, the fields are kept in the order of the configuration file.
+func hostsYaml(hosts []kubekeyapiv1alpha2.HostCfg) (string, error) {
+	var items []yaml.MapSlice
+	for _, host := range hosts {
+		item := yaml.MapSlice{
+			{Key: "name", Value: host.Name},
+			{Key: "address", Value: host.Address},
+			{Key: "internalAddress", Value: host.InternalAddress},
+		}
+		if host.InternalIPv6 != "" {
+			item = append(item, yaml.MapItem{Key: "internalIPv6", Value: host.InternalIPv6})
+		}
+		if host.Port != 0 {
+			item = append(item, yaml.MapItem{Key: "port", Value: host.Port})
+		}
+		for _, field := range []yaml.MapItem{
+			{Key: "user", Value: host.User},
+			{Key: "password", Value: host.Password},
+			{Key: "privateKey", Value: host.PrivateKey},
+			{Key: "privateKeyPath", Value: host.PrivateKeyPath},
+			{Key: "arch", Value: host.Arch},
+		} {
+			if field.Value != "" {
+				item = append(item, field)
+			}
+		}
+		if len(host.Labels) != 0 {
+			item = append(item, yaml.MapItem{Key: "labels", Value: host.Labels})
+		}
+		if len(host.Taints) != 0 {
+			var taints []yaml.MapSlice
+			for _, taint := range host.Taints {
+				t := yaml.MapSlice{{Key: "key", Value: taint.Key}}
+				if taint.Value != "" {
+					t = append(t, yaml.MapItem{Key: "value", Value: taint.Value})
+				}
+				taints = append(taints, append(t, yaml.MapItem{Key: "effect", Value: string(taint.Effect)}))
+			}
+			item = append(item, yaml.MapItem{Key: "taints", Value: taints})
+		}
+		items = append(items, item)
+	}
+
+	content, err := yaml.Marshal(items)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to generate the hosts of cluster config")
+	}
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	for i := range lines {
+		lines[i] = "  " + lines[i]
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
 // GenerateConfigFromCluster is used to generate cluster configuration file from the existing cluster's information.
-func GenerateConfigFromCluster(cfgPath, kubeconfig, name string) error {
-	opt, err := GetInfoFromCluster(kubeconfig, name)
+func GenerateConfigFromCluster(cfgPath, kubeconfig, name, hostCredentialsPath string) error {
+	var credentials *HostCredentials
+	if hostCredentialsPath != "" {
+		c, err := LoadHostCredentials(hostCredentialsPath)
+		if err != nil {
+			return err
+		}
+		credentials = c
+	}
+
+	opt, err := GetInfoFromCluster(kubeconfig, name, credentials)
 	if err != nil {
 		return err
 	}
@@ -263,6 +621,9 @@ func GenerateConfigFromCluster(cfgPath, kubeconfig, name string) error {
 
 	}
 	notice := "Notice: " + fmt.Sprintf("%s has been created. Some parameters need to be filled in by yourself, please complete it.", configPath)
+	if opt.CredentialsProvided {
+		notice = "Notice: " + fmt.Sprintf("%s has been created. Please check it before using.", configPath)
+	}
 	fmt.Printf("\033[1;36m%s\033[0m\n\n", notice)
 	return nil
 }
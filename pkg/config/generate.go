@@ -36,7 +36,7 @@ import (
 var (
 	// ClusterObjTempl defines the template of cluster configuration file default.
 	ClusterObjTempl = template.Must(template.New("Cluster").Parse(
-		dedent.Dedent(`apiVersion: kubekey.kubesphere.io/v1alpha1
+		dedent.Dedent(`apiVersion: kubekey.kubesphere.io/v1alpha2
 kind: Cluster
 metadata:
   name: {{ .Options.Name }}
@@ -53,12 +53,14 @@ spec:
     - node1
     - node2
   controlPlaneEndpoint:
+    ##Internal loadbalancer for apiservers
+    #internalLoadbalancer: haproxy
+
     domain: lb.kubesphere.local
     address: ""
     port: 6443
   kubernetes:
     version: {{ .Options.KubeVersion }}
-    imageRepo: kubesphere
     clusterName: cluster.local
   network:
     plugin: calico
@@ -92,9 +94,9 @@ func GenerateClusterObjStr(opt *Options) (string, error) {
 }
 
 // GenerateClusterObj is used to generate cluster configuration file
-func GenerateClusterObj(k8sVersion, ksVersion, name, kubeconfig, clusterCfgPath string, ksEnabled, fromCluster bool) error {
+func GenerateClusterObj(k8sVersion, ksVersion, name, kubeconfig, clusterCfgPath, hostCredentials string, ksEnabled, fromCluster bool) error {
 	if fromCluster {
-		err := GenerateConfigFromCluster(clusterCfgPath, kubeconfig, name)
+		err := GenerateConfigFromCluster(clusterCfgPath, kubeconfig, name, hostCredentials)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// ClusterCfgSources defines where the cluster configuration comes from and how it is overridden on the command line.
+type ClusterCfgSources struct {
+	// Files are merged in order, values in later files take precedence over earlier ones.
+	Files []string
+	// SetValues are applied on top of the merged files, e.g. "spec.kubernetes.version=v1.18.8".
+	SetValues []string
+	// VarFiles are the YAML files defining the variables used to render the files.
+	VarFiles []string
+	// Vars are the "name=value" variables used to render the files, they take precedence over the ones in VarFiles and in the files.
+	Vars []string
+	// Strict rejects the files containing unknown fields instead of ignoring them with a warning.
+	Strict bool
+}
+
+var indexedKeyRegexp = regexp.MustCompile(`^(.*)\[(\d+)\]$`)
+
+// mergeValues merges src into dst recursively. Maps are merged key by key, any other value (including lists) in src replaces the one in dst.
+func mergeValues(dst, src map[interface{}]interface{}) map[interface{}]interface{} {
+	if dst == nil {
+		dst = map[interface{}]interface{}{}
+	}
+	for k, v := range src {
+		srcMap, srcIsMap := v.(map[interface{}]interface{})
+		dstMap, dstIsMap := dst[k].(map[interface{}]interface{})
+		if srcIsMap && dstIsMap {
+			dst[k] = mergeValues(dstMap, srcMap)
+		} else {
+			dst[k] = v
+		}
+	}
+	return dst
+}
+
+// ApplySetValues applies the given "path=value" overrides to the cluster object in memory.
+func ApplySetValues(clusterCfg *kubekeyapiv1alpha1.Cluster, setValues []string) error {
+	if len(setValues) == 0 {
+		return nil
+	}
+
+	content, err := yaml.Marshal(map[string]interface{}{"spec": clusterCfg.Spec})
+	if err != nil {
+		return errors.Wrap(err, "Failed to marshal cluster spec")
+	}
+	values := map[interface{}]interface{}{}
+	if err := yaml.Unmarshal(content, &values); err != nil {
+		return errors.Wrap(err, "Failed to unmarshal cluster spec")
+	}
+
+	for _, setValue := range setValues {
+		kv := strings.SplitN(setValue, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return errors.New(fmt.Sprintf("Invalid value: %s, it should be in the format of path=value", setValue))
+		}
+		path := strings.Split(strings.TrimSpace(kv[0]), ".")
+		if path[0] != "spec" {
+			return errors.New(fmt.Sprintf("Invalid path: %s, only the fields under spec can be overridden", kv[0]))
+		}
+		if !isKnownPath(clusterType, path) {
+			return errors.New(fmt.Sprintf("Invalid path: %s, no such field in the cluster configuration", kv[0]))
+		}
+		var value interface{}
+		if err := yaml.Unmarshal([]byte(kv[1]), &value); err != nil {
+			return errors.Wrapf(err, "Failed to parse the value of %s", kv[0])
+		}
+		if err := setValueByPath(values, path, value); err != nil {
+			return errors.Wrapf(err, "Failed to set %s", kv[0])
+		}
+	}
+
+	content, err = yaml.Marshal(values)
+	if err != nil {
+		return errors.Wrap(err, "Failed to marshal cluster spec")
+	}
+	spec := struct {
+		Spec kubekeyapiv1alpha1.ClusterSpec `yaml:"spec"`
+	}{}
+	if err := yaml.Unmarshal(content, &spec); err != nil {
+		return errors.Wrap(err, "Failed to convert the overridden values to cluster spec")
+	}
+	clusterCfg.Spec = spec.Spec
+	return nil
+}
+
+// setValueByPath sets the value at the given path, path elements may refer to list items like "hosts[0]".
+func setValueByPath(values map[interface{}]interface{}, path []string, value interface{}) error {
+	key := path[0]
+	index := -1
+	if match := indexedKeyRegexp.FindStringSubmatch(key); match != nil {
+		key = match[1]
+		index, _ = strconv.Atoi(match[2])
+	}
+
+	if index < 0 {
+		if len(path) == 1 {
+			values[key] = value
+			return nil
+		}
+		next, ok := values[key].(map[interface{}]interface{})
+		if !ok {
+			next = map[interface{}]interface{}{}
+			values[key] = next
+		}
+		return setValueByPath(next, path[1:], value)
+	}
+
+	list, _ := values[key].([]interface{})
+	if index >= len(list) {
+		return errors.New(fmt.Sprintf("index %d out of range, %s has %d item(s)", index, key, len(list)))
+	}
+	if len(path) == 1 {
+		list[index] = value
+		return nil
+	}
+	next, ok := list[index].(map[interface{}]interface{})
+	if !ok {
+		next = map[interface{}]interface{}{}
+		list[index] = next
+	}
+	return setValueByPath(next, path[1:], value)
+}
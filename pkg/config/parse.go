@@ -27,95 +27,131 @@ import (
 	"strings"
 
 	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha2"
 	"github.com/kubesphere/kubekey/pkg/kubesphere"
 	"github.com/kubesphere/kubekey/pkg/util"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v2"
 	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	sigsyaml "sigs.k8s.io/yaml"
 )
 
 // ParseClusterCfg is used to generate Cluster object and cluster's name.
-func ParseClusterCfg(clusterCfgPath, k8sVersion, ksVersion string, ksEnabled bool, logger *log.Logger) (*kubekeyapiv1alpha1.Cluster, string, error) {
+func ParseClusterCfg(sources *ClusterCfgSources, k8sVersion, ksVersion string, ksEnabled bool, logger *log.Logger) (*kubekeyapiv1alpha1.Cluster, string, error) {
 	var (
 		clusterCfg *kubekeyapiv1alpha1.Cluster
 		objName    string
 	)
-	if len(clusterCfgPath) == 0 {
+	if sources == nil {
+		sources = &ClusterCfgSources{}
+	}
+	if len(sources.Files) == 0 {
 		currentUser, _ := user.Current()
 		if currentUser.Username != "root" {
 			return nil, "", errors.New(fmt.Sprintf("Current user is %s. Please use root!", currentUser.Username))
 		}
 		clusterCfg, objName = AllinoneCfg(currentUser, k8sVersion, ksVersion, ksEnabled, logger)
 	} else {
-		cfg, name, err := ParseCfg(clusterCfgPath, k8sVersion, ksVersion, ksEnabled)
+		cfg, name, err := ParseCfg(sources, k8sVersion, ksVersion, ksEnabled)
 		if err != nil {
 			return nil, "", err
 		}
 		clusterCfg = cfg
 		objName = name
 	}
+	if err := ApplySetValues(clusterCfg, sources.SetValues); err != nil {
+		return nil, "", err
+	}
 	return clusterCfg, objName, nil
 }
 
-// ParseCfg is used to parse the specified cluster configuration file.
-func ParseCfg(clusterCfgPath, k8sVersion, ksVersion string, ksEnabled bool) (*kubekeyapiv1alpha1.Cluster, string, error) {
+// ParseCfg is used to parse the specified cluster configuration files.
+// The Cluster documents in the files are merged in order, and the given kubernetes version is applied in memory.
+// Both the v1alpha1 and v1alpha2 Cluster documents are accepted. The unknown fields are ignored with a warning, or rejected in strict mode.
+// If any variables are defined, by the "vars" documents in the files or on the command line, the documents are rendered with them before parsing.
+func ParseCfg(sources *ClusterCfgSources, k8sVersion, ksVersion string, ksEnabled bool) (*kubekeyapiv1alpha1.Cluster, string, error) {
 	var objName string
+	var clusterValues map[interface{}]interface{}
 	clusterCfg := kubekeyapiv1alpha1.Cluster{}
-	fp, err := filepath.Abs(clusterCfgPath)
-	if err != nil {
-		return nil, "", errors.Wrap(err, "Failed to look up current directory")
+
+	type cfgDoc struct {
+		file    string
+		content []byte
 	}
-	if len(k8sVersion) != 0 {
-		_ = exec.Command("/bin/sh", "-c", fmt.Sprintf("sed -i \"/version/s/\\:.*/\\: %s/g\" %s", k8sVersion, fp)).Run()
+	var docs []cfgDoc
+	vars := map[string]interface{}{}
+	for _, clusterCfgPath := range sources.Files {
+		fp, err := filepath.Abs(clusterCfgPath)
+		if err != nil {
+			return nil, "", errors.Wrap(err, "Failed to look up current directory")
+		}
+		contents, err := readCfgFile(fp)
+		if err != nil {
+			return nil, "", err
+		}
+		for _, content := range contents {
+			if fileVars, ok := varsDoc(content); ok {
+				for k, v := range fileVars {
+					vars[k] = v
+				}
+				continue
+			}
+			docs = append(docs, cfgDoc{file: fp, content: content})
+		}
 	}
-	file, err := os.Open(fp)
+	cmdVars, err := loadVars(sources.VarFiles, sources.Vars)
 	if err != nil {
-		return nil, "", errors.Wrap(err, "Unable to open the given cluster configuration file")
+		return nil, "", err
 	}
-	defer file.Close()
-	b1 := bufio.NewReader(file)
-	for {
-		result := make(map[string]interface{})
-		content, err := k8syaml.NewYAMLReader(b1).Read()
-		if len(content) == 0 {
-			break
+	for k, v := range cmdVars {
+		vars[k] = v
+	}
+
+	for _, doc := range docs {
+		content := doc.content
+		if len(vars) != 0 {
+			if content, err = renderCfgDoc(doc.file, content, vars); err != nil {
+				return nil, "", err
+			}
 		}
+		values, err := parseCfgDoc(content, &clusterCfg)
 		if err != nil {
-			return nil, "", errors.Wrap(err, "Unable to read the given cluster configuration file")
+			return nil, "", err
 		}
-		err = yaml.Unmarshal(content, &result)
-		if err != nil {
-			return nil, "", errors.Wrap(err, "Unable to unmarshal the given cluster configuration file")
+		if values != nil {
+			clusterValues = mergeValues(clusterValues, values)
 		}
-		if result["kind"] == "Cluster" {
-			if err := yaml.Unmarshal(content, &clusterCfg); err != nil {
-				return nil, "", errors.Wrap(err, "Unable to convert file to yaml")
+	}
+
+	if clusterValues != nil {
+		if unknown := unknownFields(clusterValues, clusterType, ""); len(unknown) != 0 {
+			if sources.Strict {
+				return nil, "", errors.New(fmt.Sprintf("Unknown fields in the cluster configuration: %s", strings.Join(unknown, ", ")))
 			}
-			metadata := result["metadata"].(map[interface{}]interface{})
-			objName = metadata["name"].(string)
-		}
-
-		if result["kind"] == "ConfigMap" || result["kind"] == "ClusterConfiguration" {
-			metadata := result["metadata"].(map[interface{}]interface{})
-			labels := metadata["labels"].(map[interface{}]interface{})
-			clusterCfg.Spec.KubeSphere.Enabled = true
-			_, ok := labels["version"]
-			if ok {
-				switch labels["version"] {
-				case "v3.0.0":
-					clusterCfg.Spec.KubeSphere.Configurations = "---\n" + string(content)
-					clusterCfg.Spec.KubeSphere.Version = "v3.0.0"
-				case "v2.1.1":
-					clusterCfg.Spec.KubeSphere.Configurations = "---\n" + string(content)
-					clusterCfg.Spec.KubeSphere.Version = "v2.1.1"
-				default:
-					return nil, "", errors.Wrap(err, fmt.Sprintf("Unsupported version: %s", labels["version"]))
-				}
+			log.Warnf("Unknown fields in the cluster configuration are ignored: %s", strings.Join(unknown, ", "))
+		}
+
+		content, err := yaml.Marshal(clusterValues)
+		if err != nil {
+			return nil, "", errors.Wrap(err, "Unable to merge the given cluster configuration files")
+		}
+		if clusterValues["apiVersion"] == kubekeyapiv1alpha2.GroupVersion.String() {
+			if err := convertFromV1alpha2(content, &clusterCfg); err != nil {
+				return nil, "", err
 			}
+		} else if err := yaml.Unmarshal(content, &clusterCfg); err != nil {
+			return nil, "", errors.Wrap(err, "Unable to convert file to yaml")
+		}
+		if metadata, ok := clusterValues["metadata"].(map[interface{}]interface{}); ok {
+			objName, _ = metadata["name"].(string)
 		}
 	}
 
+	if len(k8sVersion) != 0 {
+		clusterCfg.Spec.Kubernetes.Version = k8sVersion
+	}
+
 	if ksEnabled {
 		clusterCfg.Spec.KubeSphere.Enabled = true
 		switch strings.TrimSpace(ksVersion) {
@@ -141,6 +177,78 @@ func ParseCfg(clusterCfgPath, k8sVersion, ksVersion string, ksEnabled bool) (*ku
 	return &clusterCfg, objName, nil
 }
 
+// readCfgFile is used to read the documents of a cluster configuration file.
+func readCfgFile(fp string) ([][]byte, error) {
+	var contents [][]byte
+	file, err := os.Open(fp)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to open the given cluster configuration file")
+	}
+	defer file.Close()
+	b1 := bufio.NewReader(file)
+	for {
+		content, err := k8syaml.NewYAMLReader(b1).Read()
+		if len(content) == 0 {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "Unable to read the given cluster configuration file")
+		}
+		contents = append(contents, content)
+	}
+	return contents, nil
+}
+
+// parseCfgDoc is used to parse a document of the cluster configuration file.
+// It returns the raw values of the Cluster document, the KubeSphere configurations found in the document are set to clusterCfg directly.
+func parseCfgDoc(content []byte, clusterCfg *kubekeyapiv1alpha1.Cluster) (map[interface{}]interface{}, error) {
+	var clusterValues map[interface{}]interface{}
+	result := make(map[interface{}]interface{})
+	if err := yaml.Unmarshal(content, &result); err != nil {
+		return nil, errors.Wrap(err, "Unable to unmarshal the given cluster configuration file")
+	}
+	if result["kind"] == "Cluster" {
+		clusterValues = result
+	}
+
+	if result["kind"] == "ConfigMap" || result["kind"] == "ClusterConfiguration" {
+		metadata := result["metadata"].(map[interface{}]interface{})
+		labels := metadata["labels"].(map[interface{}]interface{})
+		clusterCfg.Spec.KubeSphere.Enabled = true
+		_, ok := labels["version"]
+		if ok {
+			switch labels["version"] {
+			case "v3.0.0":
+				clusterCfg.Spec.KubeSphere.Configurations = "---\n" + string(content)
+				clusterCfg.Spec.KubeSphere.Version = "v3.0.0"
+			case "v2.1.1":
+				clusterCfg.Spec.KubeSphere.Configurations = "---\n" + string(content)
+				clusterCfg.Spec.KubeSphere.Version = "v2.1.1"
+			default:
+				return nil, errors.New(fmt.Sprintf("Unsupported version: %s", labels["version"]))
+			}
+		}
+	}
+	return clusterValues, nil
+}
+
+// convertFromV1alpha2 converts the Cluster document of v1alpha2 to the version used by kk internally.
+// The KubeSphere configurations given by separate documents are kept unless KubeSphere is configured in the Cluster document.
+func convertFromV1alpha2(content []byte, clusterCfg *kubekeyapiv1alpha1.Cluster) error {
+	cluster := kubekeyapiv1alpha2.Cluster{}
+	if err := sigsyaml.Unmarshal(content, &cluster); err != nil {
+		return errors.Wrap(err, "Unable to convert file to yaml")
+	}
+	kubeSphere := clusterCfg.Spec.KubeSphere
+	if err := clusterCfg.ConvertFrom(&cluster); err != nil {
+		return errors.Wrap(err, "Failed to convert the cluster configuration from v1alpha2")
+	}
+	if !clusterCfg.Spec.KubeSphere.Enabled {
+		clusterCfg.Spec.KubeSphere = kubeSphere
+	}
+	return nil
+}
+
 // AllinoneCfg is used to generate cluster object for all-in-one mode.
 func AllinoneCfg(user *user.User, k8sVersion, ksVersion string, ksEnabled bool, logger *log.Logger) (*kubekeyapiv1alpha1.Cluster, string) {
 	allinoneCfg := kubekeyapiv1alpha1.Cluster{}
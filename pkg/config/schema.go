@@ -0,0 +1,215 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	clusterType = reflect.TypeOf(kubekeyapiv1alpha2.Cluster{})
+	timeType    = reflect.TypeOf(metav1.Time{})
+	// apiPkgPath is the package of the types whose fields are known, the types of other packages are treated as opaque values.
+	apiPkgPath = clusterType.PkgPath()
+)
+
+// ClusterSchema generates the JSON Schema of the Cluster document in the cluster configuration file.
+// It is generated from the Cluster types of v1alpha2, the descriptions and enums come from the tags of the fields.
+func ClusterSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"$schema":     "http://json-schema.org/draft-07/schema#",
+		"title":       "Cluster",
+		"description": "The cluster configuration file of KubeKey.",
+		"type":        "object",
+		"required":    []string{"apiVersion", "kind", "spec"},
+		"properties": map[string]interface{}{
+			"apiVersion": map[string]interface{}{
+				"type": "string",
+				"enum": []string{kubekeyapiv1alpha1.GroupVersion.String(), kubekeyapiv1alpha2.GroupVersion.String()},
+			},
+			"kind": map[string]interface{}{
+				"type": "string",
+				"enum": []string{"Cluster"},
+			},
+			"metadata": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "The name of the cluster.",
+					},
+				},
+			},
+			"spec": typeSchema(reflect.TypeOf(kubekeyapiv1alpha2.ClusterSpec{})),
+		},
+	}
+}
+
+func typeSchema(t reflect.Type) map[string]interface{} {
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeSchema(t.Elem())
+	case reflect.Struct:
+		if t.PkgPath() != apiPkgPath {
+			return map[string]interface{}{"type": "object"}
+		}
+		properties := map[string]interface{}{}
+		for name, field := range jsonFields(t) {
+			schema := typeSchema(field.Type)
+			if description := field.Tag.Get("description"); description != "" {
+				schema["description"] = description
+			}
+			if enum := field.Tag.Get("enum"); enum != "" {
+				schema["enum"] = strings.Split(enum, ",")
+			}
+			properties[name] = schema
+		}
+		return map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// jsonFields returns the fields of the struct by their names in the configuration file, the inlined structs are flattened.
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := map[string]reflect.StructField{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("json"), ",")
+		name := tag[0]
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			for n, f := range jsonFields(field.Type) {
+				fields[n] = f
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+// unknownFields returns the paths of the keys which are not recognized by the given type.
+func unknownFields(value interface{}, t reflect.Type, path string) []string {
+	var unknown []string
+	switch t.Kind() {
+	case reflect.Ptr:
+		return unknownFields(value, t.Elem(), path)
+	case reflect.Struct:
+		values, ok := value.(map[interface{}]interface{})
+		if !ok || t.PkgPath() != apiPkgPath {
+			return nil
+		}
+		fields := jsonFields(t)
+		for k, v := range values {
+			key := joinFieldPath(path, fmt.Sprint(k))
+			field, ok := fields[fmt.Sprint(k)]
+			if !ok {
+				unknown = append(unknown, key)
+				continue
+			}
+			unknown = append(unknown, unknownFields(v, field.Type, key)...)
+		}
+	case reflect.Slice:
+		items, _ := value.([]interface{})
+		for i, item := range items {
+			unknown = append(unknown, unknownFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case reflect.Map:
+		values, _ := value.(map[interface{}]interface{})
+		for k, v := range values {
+			unknown = append(unknown, unknownFields(v, t.Elem(), joinFieldPath(path, fmt.Sprint(k)))...)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// isKnownPath checks whether the path like "spec.hosts[0].port" refers to a field of the given type.
+func isKnownPath(t reflect.Type, path []string) bool {
+	if len(path) == 0 {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return isKnownPath(t.Elem(), path)
+	case reflect.Struct:
+		if t.PkgPath() != apiPkgPath {
+			return true
+		}
+		key := path[0]
+		indexed := false
+		if match := indexedKeyRegexp.FindStringSubmatch(key); match != nil {
+			key = match[1]
+			indexed = true
+		}
+		field, ok := jsonFields(t)[key]
+		if !ok {
+			return false
+		}
+		fieldType := field.Type
+		if indexed {
+			if fieldType.Kind() != reflect.Slice {
+				return false
+			}
+			fieldType = fieldType.Elem()
+		}
+		return isKnownPath(fieldType, path[1:])
+	case reflect.Map:
+		return isKnownPath(t.Elem(), path[1:])
+	default:
+		return false
+	}
+}
+
+func joinFieldPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,363 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strconv"
+
+	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+	"github.com/pkg/errors"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+var hostsRangeRegexp = regexp.MustCompile(`^(.*)\[(\d+)\:(\d+)\]$`)
+
+// RemoveNodesFromCfgFiles removes the given nodes from the hosts and the role groups of the Cluster documents in the files.
+// The files are decoded and encoded as YAML nodes, so the comments and the order of the fields are kept.
+// The files which do not contain the nodes are left untouched.
+func RemoveNodesFromCfgFiles(files []string, nodeNames []string) error {
+	names := map[string]bool{}
+	for _, name := range nodeNames {
+		names[name] = true
+	}
+	for _, file := range files {
+		if err := removeNodesFromCfgFile(file, names); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Failed to update the config file %s", file))
+		}
+	}
+	return nil
+}
+
+func removeNodesFromCfgFile(file string, names map[string]bool) error {
+	return updateCfgFile(file, func(doc *yamlv3.Node) bool {
+		return removeNodesFromClusterDoc(doc, names)
+	})
+}
+
+// ReplaceNodeInCfgFiles replaces the old node with the new host in the hosts, the role groups and the kubelet pools of the Cluster documents in the files.
+// Like RemoveNodesFromCfgFiles, the comments and the order of the fields are kept.
+func ReplaceNodeInCfgFiles(files []string, oldName string, newHost kubekeyapiv1alpha1.HostCfg) error {
+	hostNode, err := hostToYamlNode(newHost)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := updateCfgFile(file, func(doc *yamlv3.Node) bool {
+			return replaceNodeInClusterDoc(doc, oldName, newHost.Name, hostNode)
+		}); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Failed to update the config file %s", file))
+		}
+	}
+	return nil
+}
+
+// updateCfgFile applies the update to every document of the file, and writes the file back if any document is changed.
+func updateCfgFile(file string, update func(doc *yamlv3.Node) bool) error {
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	var docs []*yamlv3.Node
+	decoder := yamlv3.NewDecoder(bytes.NewReader(content))
+	for {
+		doc := &yamlv3.Node{}
+		if err := decoder.Decode(doc); err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		docs = append(docs, doc)
+	}
+
+	changed := false
+	for _, doc := range docs {
+		if update(doc) {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	buf := &bytes.Buffer{}
+	encoder := yamlv3.NewEncoder(buf)
+	encoder.SetIndent(2)
+	for _, doc := range docs {
+		if err := encoder.Encode(doc); err != nil {
+			return err
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, buf.Bytes(), info.Mode())
+}
+
+// clusterSpecNode returns the spec of the document if it is a Cluster.
+func clusterSpecNode(doc *yamlv3.Node) *yamlv3.Node {
+	if doc.Kind != yamlv3.DocumentNode || len(doc.Content) == 0 {
+		return nil
+	}
+	root := doc.Content[0]
+	if kind := mappingValue(root, "kind"); kind == nil || kind.Value != "Cluster" {
+		return nil
+	}
+	return mappingValue(root, "spec")
+}
+
+// removeNodesFromClusterDoc returns whether the document is a Cluster which is changed.
+func removeNodesFromClusterDoc(doc *yamlv3.Node, names map[string]bool) bool {
+	spec := clusterSpecNode(doc)
+	if spec == nil {
+		return false
+	}
+
+	changed := false
+	if hosts := mappingValue(spec, "hosts"); hosts != nil && hosts.Kind == yamlv3.SequenceNode {
+		var content []*yamlv3.Node
+		for _, host := range hosts.Content {
+			if name := mappingValue(host, "name"); name != nil && names[name.Value] {
+				changed = true
+				continue
+			}
+			content = append(content, host)
+		}
+		hosts.Content = content
+	}
+	if roleGroups := mappingValue(spec, "roleGroups"); roleGroups != nil && roleGroups.Kind == yamlv3.MappingNode {
+		for i := 1; i < len(roleGroups.Content); i += 2 {
+			group := roleGroups.Content[i]
+			if group.Kind != yamlv3.SequenceNode {
+				continue
+			}
+			var content []*yamlv3.Node
+			for _, item := range group.Content {
+				items := removeNodesFromRoleItem(item, names)
+				if len(items) != 1 || items[0] != item {
+					changed = true
+				}
+				content = append(content, items...)
+			}
+			group.Content = content
+		}
+	}
+	return changed
+}
+
+// replaceNodeInClusterDoc returns whether the document is a Cluster which is changed.
+func replaceNodeInClusterDoc(doc *yamlv3.Node, oldName, newName string, hostNode *yamlv3.Node) bool {
+	spec := clusterSpecNode(doc)
+	if spec == nil {
+		return false
+	}
+
+	changed := false
+	if hosts := mappingValue(spec, "hosts"); hosts != nil && hosts.Kind == yamlv3.SequenceNode {
+		for i, host := range hosts.Content {
+			if name := mappingValue(host, "name"); name != nil && name.Value == oldName {
+				newHostNode := *hostNode
+				newHostNode.Style = host.Style
+				newHostNode.HeadComment, newHostNode.LineComment, newHostNode.FootComment = host.HeadComment, host.LineComment, host.FootComment
+				hosts.Content[i] = &newHostNode
+				changed = true
+			}
+		}
+	}
+	if roleGroups := mappingValue(spec, "roleGroups"); roleGroups != nil && roleGroups.Kind == yamlv3.MappingNode {
+		names := map[string]bool{oldName: true}
+		for i := 1; i < len(roleGroups.Content); i += 2 {
+			group := roleGroups.Content[i]
+			if group.Kind != yamlv3.SequenceNode {
+				continue
+			}
+			var content []*yamlv3.Node
+			replaced := false
+			for _, item := range group.Content {
+				items := removeNodesFromRoleItem(item, names)
+				if len(items) != 1 || items[0] != item {
+					replaced = true
+				}
+				content = append(content, items...)
+			}
+			if replaced {
+				content = append(content, &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: newName})
+				changed = true
+			}
+			group.Content = content
+		}
+	}
+	if kubeletPools := mappingValue(mappingValue(spec, "kubernetes"), "kubeletPools"); kubeletPools != nil && kubeletPools.Kind == yamlv3.SequenceNode {
+		for _, pool := range kubeletPools.Content {
+			if hosts := mappingValue(pool, "hosts"); hosts != nil && hosts.Kind == yamlv3.SequenceNode {
+				for _, host := range hosts.Content {
+					if host.Kind == yamlv3.ScalarNode && host.Value == oldName {
+						host.Value = newName
+						changed = true
+					}
+				}
+			}
+		}
+	}
+	return changed
+}
+
+// hostToYamlNode converts the host to a YAML node by its json tags, the styles of JSON are cleared.
+func hostToYamlNode(host kubekeyapiv1alpha1.HostCfg) (*yamlv3.Node, error) {
+	content, err := json.Marshal(host)
+	if err != nil {
+		return nil, err
+	}
+	doc := &yamlv3.Node{}
+	if err := yamlv3.Unmarshal(content, doc); err != nil {
+		return nil, err
+	}
+	clearStyle(doc)
+	return doc.Content[0], nil
+}
+
+func clearStyle(node *yamlv3.Node) {
+	node.Style = 0
+	for _, n := range node.Content {
+		clearStyle(n)
+	}
+}
+
+// ReplaceNodeInClusterSpec replaces the old node with the new host in the hosts, the role groups and the kubelet pools of the cluster spec.
+func ReplaceNodeInClusterSpec(spec *kubekeyapiv1alpha1.ClusterSpec, oldName string, newHost kubekeyapiv1alpha1.HostCfg) {
+	for i := range spec.Hosts {
+		if spec.Hosts[i].Name == oldName {
+			spec.Hosts[i] = newHost
+		}
+	}
+
+	replaceInRoleGroup := func(group []string) []string {
+		var newGroup []string
+		replaced := false
+		for _, item := range group {
+			items, removed := removeNodesFromRange(item, map[string]bool{oldName: true})
+			if removed {
+				replaced = true
+			}
+			newGroup = append(newGroup, items...)
+		}
+		if replaced {
+			newGroup = append(newGroup, newHost.Name)
+		}
+		return newGroup
+	}
+	spec.RoleGroups.Etcd = replaceInRoleGroup(spec.RoleGroups.Etcd)
+	spec.RoleGroups.Master = replaceInRoleGroup(spec.RoleGroups.Master)
+	spec.RoleGroups.Worker = replaceInRoleGroup(spec.RoleGroups.Worker)
+	spec.RoleGroups.LoadBalancer = replaceInRoleGroup(spec.RoleGroups.LoadBalancer)
+
+	for i := range spec.Kubernetes.KubeletPools {
+		for j, host := range spec.Kubernetes.KubeletPools[i].Hosts {
+			if host == oldName {
+				spec.Kubernetes.KubeletPools[i].Hosts[j] = newHost.Name
+			}
+		}
+	}
+}
+
+// removeNodesFromRoleItem returns the items left in the role group, a range like node[1:10] is split around the removed nodes.
+func removeNodesFromRoleItem(item *yamlv3.Node, names map[string]bool) []*yamlv3.Node {
+	if item.Kind != yamlv3.ScalarNode {
+		return []*yamlv3.Node{item}
+	}
+	values, removed := removeNodesFromRange(item.Value, names)
+	if !removed {
+		return []*yamlv3.Node{item}
+	}
+
+	var items []*yamlv3.Node
+	for _, value := range values {
+		newItem := *item
+		if len(items) > 0 {
+			// the comments are kept by the first part of the range only
+			newItem.HeadComment, newItem.LineComment, newItem.FootComment = "", "", ""
+		}
+		newItem.Value = value
+		items = append(items, &newItem)
+	}
+	return items
+}
+
+// removeNodesFromRange returns the hosts left in the item of a role group, which is a host name or a range like node[1:10].
+func removeNodesFromRange(item string, names map[string]bool) ([]string, bool) {
+	match := hostsRangeRegexp.FindStringSubmatch(item)
+	if match == nil {
+		if names[item] {
+			return nil, true
+		}
+		return []string{item}, false
+	}
+
+	prefix := match[1]
+	start, _ := strconv.Atoi(match[2])
+	end, _ := strconv.Atoi(match[3])
+	removed := false
+	for i := start; i <= end; i++ {
+		if names[fmt.Sprintf("%s%d", prefix, i)] {
+			removed = true
+		}
+	}
+	if !removed {
+		return []string{item}, false
+	}
+
+	var items []string
+	for i := start; i <= end; i++ {
+		if names[fmt.Sprintf("%s%d", prefix, i)] {
+			continue
+		}
+		j := i
+		for j+1 <= end && !names[fmt.Sprintf("%s%d", prefix, j+1)] {
+			j++
+		}
+		if i == j {
+			items = append(items, fmt.Sprintf("%s%d", prefix, i))
+		} else {
+			items = append(items, fmt.Sprintf("%s[%d:%d]", prefix, i, j))
+		}
+		i = j
+	}
+	return items, true
+}
+
+func mappingValue(node *yamlv3.Node, key string) *yamlv3.Node {
+	if node == nil || node.Kind != yamlv3.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRemoveNodesFromRange(t *testing.T) {
+	tests := []struct {
+		name        string
+		item        string
+		nodes       []string
+		wantItems   []string
+		wantRemoved bool
+	}{
+		{name: "host kept", item: "node1", nodes: []string{"node2"}, wantItems: []string{"node1"}},
+		{name: "host removed", item: "node1", nodes: []string{"node1"}, wantItems: nil, wantRemoved: true},
+		{name: "range kept", item: "node[1:10]", nodes: []string{"node11", "other1"}, wantItems: []string{"node[1:10]"}},
+		{name: "first of range removed", item: "node[1:10]", nodes: []string{"node1"}, wantItems: []string{"node[2:10]"}, wantRemoved: true},
+		{name: "last of range removed", item: "node[1:10]", nodes: []string{"node10"}, wantItems: []string{"node[1:9]"}, wantRemoved: true},
+		{name: "range split", item: "node[1:10]", nodes: []string{"node5"}, wantItems: []string{"node[1:4]", "node[6:10]"}, wantRemoved: true},
+		{name: "single hosts left", item: "node[1:3]", nodes: []string{"node2"}, wantItems: []string{"node1", "node3"}, wantRemoved: true},
+		{name: "range split several times", item: "node[1:10]", nodes: []string{"node3", "node4", "node8"}, wantItems: []string{"node[1:2]", "node[5:7]", "node[9:10]"}, wantRemoved: true},
+		{name: "whole range removed", item: "node[1:2]", nodes: []string{"node1", "node2"}, wantItems: nil, wantRemoved: true},
+		{name: "prefix with numbers", item: "k8s-1-[1:3]", nodes: []string{"k8s-1-2"}, wantItems: []string{"k8s-1-1", "k8s-1-3"}, wantRemoved: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names := map[string]bool{}
+			for _, node := range tt.nodes {
+				names[node] = true
+			}
+			items, removed := removeNodesFromRange(tt.item, names)
+			if !reflect.DeepEqual(items, tt.wantItems) || removed != tt.wantRemoved {
+				t.Errorf("removeNodesFromRange(%q, %v) = %v, %v, want %v, %v", tt.item, tt.nodes, items, removed, tt.wantItems, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestRemoveNodesFromCfgFiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   string
+		nodes []string
+		want  string
+	}{
+		{
+			name: "hosts and ranges removed with comments kept",
+			cfg: `# the cluster of tests
+apiVersion: kubekey.kubesphere.io/v1alpha1
+kind: Cluster
+metadata:
+  name: sample
+spec:
+  hosts:
+  # the masters
+  - {name: node1, address: 172.16.0.1, internalAddress: 172.16.0.1}
+  - {name: node2, address: 172.16.0.2, internalAddress: 172.16.0.2}
+  - {name: node3, address: 172.16.0.3, internalAddress: 172.16.0.3}
+  roleGroups:
+    etcd:
+    - node1
+    master:
+    - node1
+    worker:
+    - node[2:3] # the workers
+  kubernetes:
+    version: v1.18.8 # the version of kubernetes
+`,
+			nodes: []string{"node2"},
+			want: `# the cluster of tests
+apiVersion: kubekey.kubesphere.io/v1alpha1
+kind: Cluster
+metadata:
+  name: sample
+spec:
+  hosts:
+    # the masters
+    - {name: node1, address: 172.16.0.1, internalAddress: 172.16.0.1}
+    - {name: node3, address: 172.16.0.3, internalAddress: 172.16.0.3}
+  roleGroups:
+    etcd:
+      - node1
+    master:
+      - node1
+    worker:
+      - node3 # the workers
+  kubernetes:
+    version: v1.18.8 # the version of kubernetes
+`,
+		},
+		{
+			name: "other documents left as they are",
+			cfg: `apiVersion: kubekey.kubesphere.io/v1alpha1
+kind: Cluster
+spec:
+  hosts:
+  - {name: node1, address: 172.16.0.1}
+  - {name: node2, address: 172.16.0.2}
+  roleGroups:
+    worker:
+    - node1
+    - node2
+---
+apiVersion: v1
+kind: ConfigMap
+data:
+  node2: value
+`,
+			nodes: []string{"node2"},
+			want: `apiVersion: kubekey.kubesphere.io/v1alpha1
+kind: Cluster
+spec:
+  hosts:
+    - {name: node1, address: 172.16.0.1}
+  roleGroups:
+    worker:
+      - node1
+---
+apiVersion: v1
+kind: ConfigMap
+data:
+  node2: value
+`,
+		},
+		{
+			name: "file without the nodes untouched",
+			cfg: `apiVersion: kubekey.kubesphere.io/v1alpha1
+kind: Cluster
+spec:
+  hosts:
+  - {name: node1,   address: 172.16.0.1}
+`,
+			nodes: []string{"node2"},
+			want: `apiVersion: kubekey.kubesphere.io/v1alpha1
+kind: Cluster
+spec:
+  hosts:
+  - {name: node1,   address: 172.16.0.1}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "kubekey")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			file := filepath.Join(dir, "config.yaml")
+			if err := ioutil.WriteFile(file, []byte(tt.cfg), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := RemoveNodesFromCfgFiles([]string{file}, tt.nodes); err != nil {
+				t.Fatalf("RemoveNodesFromCfgFiles() error = %v", err)
+			}
+			got, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("RemoveNodesFromCfgFiles() got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
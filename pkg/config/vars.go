@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// varRefRegexp matches the references like "${NAME}", a reference escaped as "$${NAME}" is kept as "${NAME}" literally.
+var varRefRegexp = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// varsDoc returns the variables defined by a document containing only a "vars" block.
+func varsDoc(content []byte) (map[string]interface{}, bool) {
+	result := make(map[interface{}]interface{})
+	if err := yaml.Unmarshal(content, &result); err != nil {
+		return nil, false
+	}
+	vars, ok := result["vars"]
+	if !ok || len(result) != 1 {
+		return nil, false
+	}
+	values, _ := vars.(map[interface{}]interface{})
+	return stringKeys(values), true
+}
+
+// loadVars loads the variables given on the command line, the values given by --var take precedence over the ones in --var-file.
+func loadVars(varFiles, vars []string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	for _, varFile := range varFiles {
+		content, err := ioutil.ReadFile(varFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "Unable to read the given variable file")
+		}
+		fileValues := make(map[interface{}]interface{})
+		if err := yaml.Unmarshal(content, &fileValues); err != nil {
+			return nil, errors.Wrapf(err, "Unable to unmarshal the variable file %s", varFile)
+		}
+		for k, v := range stringKeys(fileValues) {
+			values[k] = v
+		}
+	}
+	for _, v := range vars {
+		kv := strings.SplitN(v, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, errors.New(fmt.Sprintf("Invalid variable: %s, it should be in the format of name=value", v))
+		}
+		values[strings.TrimSpace(kv[0])] = kv[1]
+	}
+	return values, nil
+}
+
+// renderCfgDoc renders a document of the configuration file with the given variables.
+// The document is executed as a Go template first, then the "${NAME}" references are substituted. Referring to an undefined variable is an error.
+func renderCfgDoc(name string, content []byte, vars map[string]interface{}) ([]byte, error) {
+	tmpl, err := template.New(name).Funcs(sprig.TxtFuncMap()).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse the template in %s", name)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return nil, errors.Wrapf(err, "Failed to render the template in %s", name)
+	}
+
+	var undefined []string
+	rendered := varRefRegexp.ReplaceAllFunc(buf.Bytes(), func(ref []byte) []byte {
+		if bytes.HasPrefix(ref, []byte("$$")) {
+			return ref[1:]
+		}
+		varName := string(varRefRegexp.FindSubmatch(ref)[1])
+		value, ok := vars[varName]
+		if !ok {
+			undefined = append(undefined, varName)
+			return ref
+		}
+		return []byte(fmt.Sprint(value))
+	})
+	if len(undefined) != 0 {
+		return nil, errors.New(fmt.Sprintf("Undefined variables in %s: %s", name, strings.Join(undefined, ", ")))
+	}
+	return rendered, nil
+}
+
+func stringKeys(values map[interface{}]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		result[fmt.Sprint(k)] = v
+	}
+	return result
+}
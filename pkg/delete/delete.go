@@ -20,22 +20,22 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"os/exec"
-	"path/filepath"
-	"strconv"
 	"strings"
-	"unicode"
+	"time"
 
 	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+	"github.com/kubesphere/kubekey/pkg/cluster/kubernetes"
+	"github.com/kubesphere/kubekey/pkg/cluster/preinstall"
 	"github.com/kubesphere/kubekey/pkg/config"
+	"github.com/kubesphere/kubekey/pkg/util"
 	"github.com/kubesphere/kubekey/pkg/util/executor"
 	"github.com/kubesphere/kubekey/pkg/util/manager"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
-func ResetCluster(clusterCfgFile string, logger *log.Logger, verbose bool) error {
-	cfg, objName, err := config.ParseClusterCfg(clusterCfgFile, "", "", false, logger)
+func ResetCluster(clusterCfgSources *config.ClusterCfgSources, logger *log.Logger, verbose bool) error {
+	cfg, objName, err := config.ParseClusterCfg(clusterCfgSources, "", "", false, logger)
 	if err != nil {
 		return errors.Wrap(err, "Failed to download cluster config")
 	}
@@ -43,72 +43,28 @@ func ResetCluster(clusterCfgFile string, logger *log.Logger, verbose bool) error
 	return Execute(executor.NewExecutor(&cfg.Spec, objName, logger, "", verbose, false, true, false, false, nil))
 }
 
-func ResetNode(clusterCfgFile string, logger *log.Logger, verbose bool, nodeName string) error {
-	if "" == nodeName {
+// DeleteNodes is used to delete the nodes from the cluster one by one, the masters and the members of etcd are supported.
+// The nodes are looked up by name in the cluster config. The config files are updated only if updateConfig is set.
+func DeleteNodes(clusterCfgSources *config.ClusterCfgSources, logger *log.Logger, verbose bool, nodeNames []string, timeout time.Duration, updateConfig bool) error {
+	if len(nodeNames) == 0 {
 		return errors.New("Node name does not exist")
 	}
-	fp, _ := filepath.Abs(clusterCfgFile)
-	cmd0 := fmt.Sprintf("cat %s | grep %s | wc -l", fp, nodeName)
-	nodeNameNum, err0 := exec.Command("/bin/sh", "-c", cmd0).CombinedOutput()
-	if err0 != nil {
-		return errors.Wrap(err0, "Failed to get node num")
-	}
-	if string(nodeNameNum) == "2\n" {
-		cmd := fmt.Sprintf("sed -i /%s/d %s", nodeName, fp)
-		_ = exec.Command("/bin/sh", "-c", cmd).Run()
-		cfg, objName, _ := config.ParseClusterCfg(clusterCfgFile, "", "", false, logger)
-		return Execute1(executor.NewExecutor(&cfg.Spec, objName, logger, "", verbose, false, true, false, false, nil))
-	} else if string(nodeNameNum) == "1\n" {
-		cmd := fmt.Sprintf("sed -i /%s/d %s", nodeName, fp)
-		_ = exec.Command("/bin/sh", "-c", cmd).Run()
-		cfg, objName, err := config.ParseClusterCfg(clusterCfgFile, "", "", false, logger)
-		if err != nil {
-			return errors.Wrap(err, "Failed to download cluster config")
-		}
-		mgr, err1 := executor.NewExecutor(&cfg.Spec, objName, logger, "", verbose, false, true, false, false, nil).CreateManager()
-		if err1 != nil {
-			return errors.Wrap(err1, "Failed to get cluster config")
-		}
-		var newNodeName []string
-		for i := 0; i < len(mgr.WorkerNodes); i++ {
-			nodename := mgr.WorkerNodes[i].Name
-			if nodeName == nodename {
-				continue
-			} else {
-				newNodeName = append(newNodeName, nodename)
-			}
-		}
-		var connNodeName []string
-		for j := 0; j < len(newNodeName); j++ {
-			t := j
-			nodename1 := newNodeName[t]
-			for t+1 < len(newNodeName) && Isadjoin(newNodeName[t], newNodeName[t+1]) {
-				t++
-			}
-			if t == j {
-				connNodeName = append(connNodeName, nodename1)
-			} else {
-				connNodeName = append(connNodeName, Merge(nodename1, newNodeName[t]))
-				j = t
-			}
-		}
-		cmd1 := fmt.Sprintf("sed -i -n '1,/worker/p;/controlPlaneEndpoint/,$p' %s", fp)
-		_ = exec.Command("/bin/sh", "-c", cmd1).Run()
-		for k := 0; k < len(connNodeName); k++ {
-			workPar := connNodeName[k]
-			workPar1 := fmt.Sprintf("%s", workPar)
-			cmd2 := fmt.Sprintf("sed -i '/worker/a\\ \\ \\ \\ \\- %s' %s", workPar1, fp)
-			_ = exec.Command("/bin/sh", "-c", cmd2).Run()
-		}
-		cfg1, objName, _ := config.ParseClusterCfg(clusterCfgFile, "", "", false, logger)
-		return Execute1(executor.NewExecutor(&cfg1.Spec, objName, logger, "", verbose, false, true, false, false, nil))
-	} else {
-		fmt.Println("Please check the node name in the config-sample.yaml or do not support to delete master")
-		os.Exit(0)
+	cfg, objName, err := config.ParseClusterCfg(clusterCfgSources, "", "", false, logger)
+	if err != nil {
+		return errors.Wrap(err, "Failed to download cluster config")
 	}
 
-	return nil
+	if err := ExecuteDeleteNodes(executor.NewExecutor(&cfg.Spec, objName, logger, "", verbose, false, true, false, false, nil), nodeNames, timeout); err != nil {
+		return err
+	}
+
+	if !updateConfig {
+		fmt.Printf("Please remove %s from the hosts and the role groups of the config, or run with --update-config\n", strings.Join(nodeNames, ", "))
+		return nil
+	}
+	return config.RemoveNodesFromCfgFiles(clusterCfgSources.Files, nodeNames)
 }
+
 func Execute(executor *executor.Executor) error {
 	mgr, err := executor.CreateManager()
 	if err != nil {
@@ -116,13 +72,60 @@ func Execute(executor *executor.Executor) error {
 	}
 	return ExecTasks(mgr)
 }
-func Execute1(executor *executor.Executor) error {
+
+func ExecuteDeleteNodes(executor *executor.Executor, nodeNames []string, timeout time.Duration) error {
 	mgr, err := executor.CreateManager()
 	if err != nil {
 		return err
 	}
-	return ExecTasks1(mgr)
+	drainTimeout = timeout
+
+	nodes := map[string]kubekeyapiv1alpha1.HostCfg{}
+	for _, node := range mgr.AllNodes {
+		nodes[node.Name] = node
+	}
+	for _, nodeName := range nodeNames {
+		if _, ok := nodes[nodeName]; !ok {
+			return errors.New(fmt.Sprintf("Node %s is not found in the cluster config", nodeName))
+		}
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	input, err := Confirm1(reader, nodeNames)
+	if err != nil {
+		return err
+	}
+	if input == "no" {
+		os.Exit(0)
+	}
+
+	for _, nodeName := range nodeNames {
+		deletingNode = nodes[nodeName]
+		// the stacked etcd of kubeadm runs on the masters, so the etcd group is used instead of the role flag
+		for _, node := range mgr.EtcdNodes {
+			if node.Name == nodeName {
+				deletingNode.IsEtcd = true
+			}
+		}
+
+		deletingNodeUnreachable = false
+		if _, err := mgr.Connector.Connect(deletingNode); err != nil {
+			mgr.Logger.Warnf("%s is unreachable, it is deleted without draining and resetting: %v", nodeName, err)
+			deletingNodeUnreachable = true
+		}
+
+		mgr = remainingManager(mgr, nodeName)
+		mgr.Logger.Infof("Deleting node %s ...\n", nodeName)
+		if err := ExecDeleteNodeTasks(mgr); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Failed to delete node %s", nodeName))
+		}
+	}
+
+	mgr.Logger.Infoln("Successful.")
+
+	return nil
 }
+
 func ExecTasks(mgr *manager.Manager) error {
 	resetTasks := []manager.Task{
 		{Task: ResetKubeCluster, ErrMsg: "Failed to reset kube cluster"},
@@ -138,19 +141,25 @@ func ExecTasks(mgr *manager.Manager) error {
 
 	return nil
 }
-func ExecTasks1(mgr *manager.Manager) error {
-	resetNodeTasks := []manager.Task{
-		{Task: ResetKubeNode, ErrMsg: "Failed to reset kube cluster"},
+
+// ExecDeleteNodeTasks deletes the node from the cluster, the given manager holds the rest of the cluster.
+func ExecDeleteNodeTasks(mgr *manager.Manager) error {
+	deleteNodeTasks := []manager.Task{
+		{Task: CheckDeletingNode, ErrMsg: "Failed to check the node to be deleted"},
+		{Task: DeleteKubeNode, ErrMsg: "Failed to delete the node from kubernetes"},
+		{Task: RemoveEtcdMember, ErrMsg: "Failed to remove the etcd member"},
+		{Task: ResetKubeNode, ErrMsg: "Failed to reset kube node"},
+		{Task: RefreshControlPlane, ErrMsg: "Failed to refresh the control plane"},
+		{Task: preinstall.RefreshHosts, ErrMsg: "Failed to refresh hosts"},
+		{Task: kubernetes.ConfigureLoadBalancer, ErrMsg: "Failed to configure the load balancers"},
+		{Task: kubernetes.DeployInternalLoadbalancer, ErrMsg: "Failed to deploy the internal load balancer"},
 	}
 
-	for _, step := range resetNodeTasks {
+	for _, step := range deleteNodeTasks {
 		if err := step.Run(mgr); err != nil {
 			return errors.Wrap(err, step.ErrMsg)
 		}
 	}
-
-	mgr.Logger.Infoln("Successful.")
-
 	return nil
 }
 
@@ -168,90 +177,212 @@ func ResetKubeCluster(mgr *manager.Manager) error {
 
 	return mgr.RunTaskOnK8sNodes(resetKubeCluster, true)
 }
-func ResetKubeNode(mgr *manager.Manager) error {
-	reader := bufio.NewReader(os.Stdin)
-	input, err := Confirm1(reader)
-	if err != nil {
-		return err
-	}
-	if input == "no" {
-		os.Exit(0)
-	}
 
-	mgr.Logger.Infoln("Resetting kubernetes node ...")
+var (
+	// deletingNode is the node which is being deleted by ExecDeleteNodeTasks.
+	deletingNode kubekeyapiv1alpha1.HostCfg
+	// deletingNodeUnreachable is set when the deleting node can not be connected, such as the hardware is failed.
+	deletingNodeUnreachable bool
+	// drainTimeout is how long to wait for the node to be drained, zero means infinite.
+	drainTimeout time.Duration
+)
 
-	return mgr.RunTaskOnMasterNodes(resetKubeNode, true)
+// remainingManager returns a copy of the manager without the deleting node, all the tasks of deleting a node are run with it.
+func remainingManager(mgr *manager.Manager, nodeName string) *manager.Manager {
+	newMgr := mgr.Copy()
+	cluster := *mgr.Cluster
+	cluster.Hosts = excludeNode(mgr.Cluster.Hosts, nodeName)
+	newMgr.Cluster = &cluster
+	newMgr.AllNodes = excludeNode(mgr.AllNodes, nodeName)
+	newMgr.EtcdNodes = excludeNode(mgr.EtcdNodes, nodeName)
+	newMgr.MasterNodes = excludeNode(mgr.MasterNodes, nodeName)
+	newMgr.WorkerNodes = excludeNode(mgr.WorkerNodes, nodeName)
+	newMgr.K8sNodes = excludeNode(mgr.K8sNodes, nodeName)
+	newMgr.LoadBalancerNodes = excludeNode(mgr.LoadBalancerNodes, nodeName)
+
+	// without a load balancer, the control plane endpoint defaults to the first master, move it to a remaining one
+	if len(newMgr.MasterNodes) > 0 && deletingNode.IsMaster && cluster.ControlPlaneEndpoint.Address == deletingNode.InternalAddress {
+		cluster.ControlPlaneEndpoint.Address = newMgr.MasterNodes[0].InternalAddress
+	}
+	newMgr.ClusterHosts = executor.GenerateHosts(&kubekeyapiv1alpha1.HostGroups{Master: newMgr.MasterNodes}, &cluster)
+	return newMgr
 }
 
-func resetKubeNode(mgr *manager.Manager, _ *kubekeyapiv1alpha1.HostCfg) error {
-	if mgr.Runner.Index == 0 {
-		var deletenodename string
-		var tmp []string
-		output1, _ := mgr.Runner.ExecuteCmd("sudo -E  /usr/local/bin/kubectl get nodes | grep -v NAME | grep -v 'master' | awk '{print $1}'", 0, true)
-		if !strings.Contains(output1, "\r\n") {
-			tmp = append(tmp, output1)
-			fmt.Println("")
-			os.Exit(0)
-		} else {
-			tmp = strings.Split(output1, "\r\n")
-		}
-		var tmp1 string
-		for j := 0; j < len(mgr.WorkerNodes); j++ {
-			tmp1 += mgr.WorkerNodes[j].Name + "\r\n"
+func excludeNode(nodes []kubekeyapiv1alpha1.HostCfg, nodeName string) []kubekeyapiv1alpha1.HostCfg {
+	var newNodes []kubekeyapiv1alpha1.HostCfg
+	for _, node := range nodes {
+		if node.Name != nodeName {
+			newNodes = append(newNodes, node)
 		}
-		for i := 0; i < len(tmp); i++ {
-			if strings.Contains(tmp1, tmp[i]) {
-				continue
-			} else {
-				deletenodename = tmp[i]
-				break
-			}
+	}
+	return newNodes
+}
+
+// CheckDeletingNode refuses to delete the last master, and checks that etcd keeps its quorum without the deleting member.
+func CheckDeletingNode(mgr *manager.Manager) error {
+	if deletingNode.IsMaster && len(mgr.MasterNodes) == 0 {
+		return errors.New(fmt.Sprintf("%s is the last master, it can not be deleted", deletingNode.Name))
+	}
+
+	if deletingNode.IsEtcd && mgr.Cluster.Etcd.Type != kubekeyapiv1alpha1.EtcdTypeExternal {
+		if len(mgr.EtcdNodes) == 0 {
+			return errors.New(fmt.Sprintf("%s is the last member of etcd, it can not be deleted", deletingNode.Name))
 		}
-		if err := DrainAndDeleteNode(mgr, deletenodename); err != nil {
+		if err := mgr.RunTaskOnNodes(mgr.EtcdNodes[:1], checkEtcdQuorum, false); err != nil {
 			return err
 		}
+		if len(mgr.EtcdNodes)%2 == 0 {
+			mgr.Logger.Warnf("The number of etcd members will be %d, an odd number is recommended", len(mgr.EtcdNodes))
+		}
+	}
+	return nil
+}
+
+func checkEtcdQuorum(mgr *manager.Manager, _ *kubekeyapiv1alpha1.HostCfg) error {
+	endpoints := mgr.Cluster.EtcdEndpoints(mgr.EtcdNodes)
+	output, _ := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s endpoint health --endpoints=%s 2>&1 || true\"", etcdctlCmd(mgr), strings.Join(endpoints, ",")), 1, false)
+
+	healthy := 0
+	for _, endpoint := range endpoints {
+		if strings.Contains(output, fmt.Sprintf("%s is healthy", endpoint)) {
+			healthy++
+		}
+	}
+	// the member is removed while it is still counted in the cluster, so the quorum of the current cluster is required
+	quorum := (len(endpoints)+1)/2 + 1
+	if healthy < quorum {
+		return errors.New(fmt.Sprintf("Only %d of the remaining %d etcd members are healthy, %d are required to keep the quorum of etcd", healthy, len(endpoints), quorum))
 	}
 	return nil
 }
-func DrainAndDeleteNode(mgr *manager.Manager, deleteNodeName string) error {
-	_, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"/usr/local/bin/kubectl drain %s --delete-local-data --ignore-daemonsets\"", deleteNodeName), 5, true)
+
+// etcdctlCmd returns the etcdctl command with the client certificate of the node it runs on.
+func etcdctlCmd(mgr *manager.Manager) string {
+	caFile, certFile, keyFile := mgr.Cluster.EtcdClientCerts(mgr.Runner.Host.Name)
+	if mgr.Cluster.Etcd.Type == kubekeyapiv1alpha1.EtcdTypeKubeKey {
+		certFile = fmt.Sprintf("/etc/ssl/etcd/ssl/admin-%s.pem", mgr.Runner.Host.Name)
+		keyFile = fmt.Sprintf("/etc/ssl/etcd/ssl/admin-%s-key.pem", mgr.Runner.Host.Name)
+	}
+	return fmt.Sprintf("export ETCDCTL_API=3;/usr/local/bin/etcdctl --cacert=%s --cert=%s --key=%s", caFile, certFile, keyFile)
+}
+
+// DeleteKubeNode drains the deleting node and deletes it from kubernetes.
+func DeleteKubeNode(mgr *manager.Manager) error {
+	if !deletingNode.IsMaster && !deletingNode.IsWorker {
+		return nil
+	}
+
+	mgr.Logger.Infoln("Draining and deleting kubernetes node ...")
+
+	return mgr.RunTaskOnNodes(mgr.MasterNodes[:1], deleteKubeNode, false)
+}
+
+func deleteKubeNode(mgr *manager.Manager, _ *kubekeyapiv1alpha1.HostCfg) error {
+	if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"/usr/local/bin/kubectl get node %s\"", deletingNode.Name), 0, false); err != nil {
+		mgr.Logger.Infof("%s is not a node of kubernetes, skip deleting it\n", deletingNode.Name)
+		return nil
+	}
+	if deletingNodeUnreachable {
+		// the pods on the unreachable node can not be evicted, they are cleaned up after the node is deleted
+		if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"/usr/local/bin/kubectl delete node %s\"", deletingNode.Name), 5, true); err != nil {
+			return errors.Wrap(err, "Failed to delete the node")
+		}
+		return nil
+	}
+	return DrainAndDeleteNode(mgr, deletingNode.Name, drainTimeout)
+}
+
+// RemoveEtcdMember removes the deleting node from the members of etcd, which is done on a remaining member.
+func RemoveEtcdMember(mgr *manager.Manager) error {
+	if !deletingNode.IsEtcd || mgr.Cluster.Etcd.Type == kubekeyapiv1alpha1.EtcdTypeExternal {
+		return nil
+	}
+
+	mgr.Logger.Infoln("Removing etcd member ...")
+
+	return mgr.RunTaskOnNodes(mgr.EtcdNodes[:1], removeEtcdMember, false)
+}
+
+func removeEtcdMember(mgr *manager.Manager, _ *kubekeyapiv1alpha1.HostCfg) error {
+	endpoints := strings.Join(mgr.Cluster.EtcdEndpoints(mgr.EtcdNodes), ",")
+	peerURL := fmt.Sprintf("https://%s:2380", deletingNode.InternalAddress)
+	memberID, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s --endpoints=%s member list | grep '%s' | cut -d ',' -f1\"", etcdctlCmd(mgr), endpoints, peerURL), 3, false)
 	if err != nil {
-		return errors.Wrap(err, "Failed to drain the node")
+		return errors.Wrap(errors.WithStack(err), "Failed to list etcd members")
 	}
-	_, err1 := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"/usr/local/bin/kubectl delete node %s\"", deleteNodeName), 5, true)
-	if err1 != nil {
-		return errors.Wrap(err1, "Failed to delete the node")
+	if strings.TrimSpace(memberID) == "" {
+		mgr.Logger.Infof("%s is not a member of etcd, skip removing it\n", deletingNode.Name)
+		return nil
+	}
+
+	if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"%s --endpoints=%s member remove %s\"", etcdctlCmd(mgr), endpoints, strings.TrimSpace(memberID)), 3, true); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to remove etcd member %s", deletingNode.Name))
 	}
 	return nil
 }
-func Merge(name1, name2 string) (endname string) {
-	par1, par2 := SplitNum(name1)
-	_, par4 := SplitNum(name2)
-	var endName string
-	endName = fmt.Sprintf("%s[%s:%s]", par1, strconv.Itoa(par2), strconv.Itoa(par4))
-	return endName
+
+// ResetKubeNode resets the deleting node, it is skipped with a warning when the node is unreachable.
+func ResetKubeNode(mgr *manager.Manager) error {
+	if deletingNodeUnreachable {
+		mgr.Logger.Warnf("%s is unreachable, please reset it manually if it is recovered", deletingNode.Name)
+		return nil
+	}
+
+	mgr.Logger.Infoln("Resetting kubernetes node ...")
+
+	if err := mgr.RunTaskOnNodes([]kubekeyapiv1alpha1.HostCfg{deletingNode}, resetKubeNode, false); err != nil {
+		mgr.Logger.Warnf("Failed to reset %s, please reset it manually: %v", deletingNode.Name, err)
+	}
+	return nil
 }
-func Isadjoin(name1, name2 string) bool {
-	Isad := false
-	par1, par2 := SplitNum(name1)
-	par3, par4 := SplitNum(name2)
-	if par1 == par3 && par4 == par2+1 {
-		Isad = true
-	}
-	return Isad
+
+func resetKubeNode(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg) error {
+	if node.IsLoadBalancer {
+		_, _ = mgr.Runner.ExecuteCmd("sudo -E /bin/sh -c \"systemctl disable --now keepalived haproxy\"", 0, true)
+	}
+	return resetKubeCluster(mgr, node)
 }
 
-func SplitNum(nodename string) (name string, num int) {
-	nodelen := len(nodename)
-	i := nodelen - 1
-	for ; nodelen > 0; i-- {
-		if !unicode.IsDigit(rune(nodename[i])) {
-			num, _ := strconv.Atoi(nodename[i+1:])
-			name := nodename[:i+1]
-			return name, num
+// RefreshControlPlane removes the deleting node from the etcd servers of kube-apiserver and from the certSANs of the kubeadm config.
+// The certificate of kube-apiserver holds the old SANs until it is renewed.
+func RefreshControlPlane(mgr *manager.Manager) error {
+	if !deletingNode.IsMaster && !deletingNode.IsEtcd {
+		return nil
+	}
+
+	mgr.Logger.Infoln("Refreshing the control plane ...")
+
+	return mgr.RunTaskOnMasterNodes(refreshControlPlane, false)
+}
+
+func refreshControlPlane(mgr *manager.Manager, _ *kubekeyapiv1alpha1.HostCfg) error {
+	if deletingNode.IsEtcd && mgr.Cluster.Etcd.Type == kubekeyapiv1alpha1.EtcdTypeKubeKey {
+		etcdServer := fmt.Sprintf("https://%s:%s", deletingNode.InternalAddress, kubekeyapiv1alpha1.DefaultEtcdPort)
+		if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"sed -i 's#%s,##g;s#,%s##g' /etc/kubernetes/manifests/kube-apiserver.yaml\"", etcdServer, etcdServer), 1, false); err != nil {
+			return errors.Wrap(errors.WithStack(err), "Failed to update the etcd servers of kube-apiserver")
 		}
 	}
-	return "", 0
+
+	if mgr.Runner.Index != 0 {
+		return nil
+	}
+	if util.IsExist(fmt.Sprintf("%s/kubeadm-config.yaml", mgr.WorkDir)) {
+		mgr.Logger.Warnf("The custom kubeadm config %s/kubeadm-config.yaml is used, please remove %s from it manually", mgr.WorkDir, deletingNode.Name)
+		return nil
+	}
+	return kubernetes.UploadClusterConfiguration(mgr)
+}
+
+func DrainAndDeleteNode(mgr *manager.Manager, deleteNodeName string, timeout time.Duration) error {
+	_, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"/usr/local/bin/kubectl drain %s --delete-local-data --ignore-daemonsets --timeout=%s\"", deleteNodeName, timeout), 5, true)
+	if err != nil {
+		return errors.Wrap(err, "Failed to drain the node")
+	}
+	_, err1 := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"/usr/local/bin/kubectl delete node %s\"", deleteNodeName), 5, true)
+	if err1 != nil {
+		return errors.Wrap(err1, "Failed to delete the node")
+	}
+	return nil
 }
 
 var clusterFiles = []string{
@@ -260,6 +391,7 @@ var clusterFiles = []string{
 	"/var/lib/etcd",
 	"/etc/etcd.env",
 	"/etc/kubernetes",
+	"/etc/kubekey",
 	"/etc/systemd/system/etcd.service",
 	"/var/log/calico",
 	"/etc/cni",
@@ -340,9 +472,9 @@ func Confirm(reader *bufio.Reader) (string, error) {
 	}
 }
 
-func Confirm1(reader *bufio.Reader) (string, error) {
+func Confirm1(reader *bufio.Reader, nodeNames []string) (string, error) {
 	for {
-		fmt.Printf("Are you sure to delete this node? [yes/no]: ")
+		fmt.Printf("Are you sure to delete the nodes %s? [yes/no]: ", strings.Join(nodeNames, ", "))
 		input, err := reader.ReadString('\n')
 		if err != nil {
 			return "", err
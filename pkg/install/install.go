@@ -42,7 +42,7 @@ import (
 )
 
 // CreateCluster is used to create cluster based on the given parameters or configuration file.
-func CreateCluster(clusterCfgFile, k8sVersion, ksVersion string, logger *log.Logger, ksEnabled, verbose, skipCheck, skipPullImages, inCluster bool) error {
+func CreateCluster(clusterCfgSources *config.ClusterCfgSources, k8sVersion, ksVersion string, logger *log.Logger, ksEnabled, verbose, skipCheck, skipPullImages, inCluster bool) error {
 	currentDir, err := filepath.Abs(filepath.Dir(os.Args[0]))
 	if err != nil {
 		return errors.Wrap(err, "Failed to get current dir")
@@ -51,7 +51,7 @@ func CreateCluster(clusterCfgFile, k8sVersion, ksVersion string, logger *log.Log
 		return errors.Wrap(err, "Failed to create work dir")
 	}
 
-	cfg, objName, err := config.ParseClusterCfg(clusterCfgFile, k8sVersion, ksVersion, ksEnabled, logger)
+	cfg, objName, err := config.ParseClusterCfg(clusterCfgSources, k8sVersion, ksVersion, ksEnabled, logger)
 	if err != nil {
 		return errors.Wrap(err, "Failed to download cluster config")
 	}
@@ -84,15 +84,28 @@ func ExecTasks(mgr *manager.Manager) error {
 		{Task: preinstall.PrePullImages, ErrMsg: "Failed to pre-pull images"},
 		{Task: etcd.GenerateEtcdCerts, ErrMsg: "Failed to generate etcd certs"},
 		{Task: etcd.SyncEtcdCertsToMaster, ErrMsg: "Failed to sync etcd certs"},
+		{Task: etcd.CheckExternalEtcd, ErrMsg: "Failed to check the external etcd"},
 		{Task: etcd.GenerateEtcdService, ErrMsg: "Failed to create etcd service"},
 		{Task: etcd.SetupEtcdCluster, ErrMsg: "Failed to start etcd cluster"},
 		{Task: etcd.RefreshEtcdConfig, ErrMsg: "Failed to refresh etcd configuration"},
 		{Task: etcd.BackupEtcd, ErrMsg: "Failed to backup etcd data"},
 		{Task: kubernetes.GetClusterStatus, ErrMsg: "Failed to get cluster status"},
 		{Task: kubernetes.InstallKubeBinaries, ErrMsg: "Failed to install kube binaries"},
+		{Task: kubernetes.SyncAuditPolicy, ErrMsg: "Failed to sync audit policy"},
+		{Task: kubernetes.SyncEncryptionConfig, ErrMsg: "Failed to sync encryption config"},
+		{Task: kubernetes.SyncAuthenticationConfig, ErrMsg: "Failed to sync authentication config"},
+		{Task: kubernetes.SyncAdmissionConfig, ErrMsg: "Failed to sync admission config"},
+		{Task: kubernetes.ConfigureLoadBalancer, ErrMsg: "Failed to configure load balancer"},
 		{Task: kubernetes.InitKubernetesCluster, ErrMsg: "Failed to init kubernetes cluster"},
+		{Task: kubernetes.DeployPodSecurityPolicy, ErrMsg: "Failed to deploy pod security policies"},
 		{Task: kubernetes.JoinNodesToCluster, ErrMsg: "Failed to join node"},
+		{Task: kubernetes.DeployInternalLoadbalancer, ErrMsg: "Failed to deploy the internal load balancer"},
+		{Task: etcd.BackupStackedEtcd, ErrMsg: "Failed to backup etcd data"},
+		{Task: kubernetes.ConfigureKubelet, ErrMsg: "Failed to configure kubelet"},
+		{Task: kubernetes.ConfigureKubeProxy, ErrMsg: "Failed to configure kube-proxy"},
+		{Task: addons.DeployCloudControllerManager, ErrMsg: "Failed to deploy cloud-controller-manager"},
 		{Task: network.DeployNetworkPlugin, ErrMsg: "Failed to deploy network plugin"},
+		{Task: kubernetes.DeployKubeletCsrApprover, ErrMsg: "Failed to deploy kubelet-csr-approver"},
 		{Task: addons.InstallAddons, ErrMsg: "Failed to deploy addons", Skip: skipCondition},
 		{Task: kubesphere.DeployLocalVolume, ErrMsg: "Failed to deploy localVolume", Skip: skipCondition},
 		{Task: kubesphere.DeployKubeSphere, ErrMsg: "Failed to deploy kubesphere", Skip: skipCondition},
@@ -19,6 +19,7 @@ package kubesphere
 import (
 	"encoding/base64"
 	"fmt"
+	"net/url"
 	"os"
 	"regexp"
 	"strings"
@@ -124,8 +125,12 @@ EOF
 	}
 
 	var addrList []string
-	for _, host := range mgr.EtcdNodes {
-		addrList = append(addrList, host.InternalAddress)
+	for _, endpoint := range mgr.Cluster.EtcdEndpoints(mgr.EtcdNodes) {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Failed to parse the etcd endpoint %s", endpoint))
+		}
+		addrList = append(addrList, u.Hostname())
 	}
 	etcdendpoint := strings.Join(addrList, ",")
 	if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo /bin/sh -c \"sed -i '/endpointIps/s/\\:.*/\\: %s/g' /etc/kubernetes/addons/kubesphere.yaml\"", etcdendpoint), 2, false); err != nil {
@@ -169,9 +174,7 @@ EOF
 		return errors.Wrap(errors.WithStack(err3), "Failed to create namespace: kubesphere-system")
 	}
 
-	caFile := "/etc/ssl/etcd/ssl/ca.pem"
-	certFile := fmt.Sprintf("/etc/ssl/etcd/ssl/node-%s.pem", mgr.EtcdNodes[0].Name)
-	keyFile := fmt.Sprintf("/etc/ssl/etcd/ssl/node-%s-key.pem", mgr.EtcdNodes[0].Name)
+	caFile, certFile, keyFile := mgr.Cluster.EtcdClientCerts(mgr.MasterNodes[0].Name)
 	if output, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"/usr/local/bin/kubectl -n kubesphere-monitoring-system create secret generic kube-etcd-client-certs --from-file=etcd-client-ca.crt=%s --from-file=etcd-client.crt=%s --from-file=etcd-client.key=%s\"", caFile, certFile, keyFile), 1, true); err != nil {
 		if !strings.Contains(output, "AlreadyExists") {
 			return err
@@ -61,7 +61,9 @@ data:
           "nodename": "__KUBERNETES_NODE_NAME__",
           "mtu": __CNI_MTU__,
           "ipam": {
-              "type": "calico-ipam"
+              "type": "calico-ipam"{{ if .KubePodsCIDRIPv6 }},
+              "assign_ipv4": "true",
+              "assign_ipv6": "true"{{ end }}
           },
           "policy": {
               "type": "k8s"
@@ -3884,14 +3886,24 @@ spec:
               value: "{{ .KubePodsCIDR }}"
             - name: CALICO_IPV4POOL_BLOCK_SIZE
               value: "{{ .NodeCidrMaskSize }}"
+            {{- if .KubePodsCIDRIPv6 }}
+            # The default IPv6 pool of a dual-stack cluster, its block size is left to the default 122 of calico
+            # as calico only accepts the IPv6 block sizes from 116 to 128.
+            - name: IP6
+              value: "autodetect"
+            - name: CALICO_IPV6POOL_CIDR
+              value: "{{ .KubePodsCIDRIPv6 }}"
+            - name: CALICO_IPV6POOL_NAT_OUTGOING
+              value: "true"
+            {{- end }}
             - name: CALICO_DISABLE_FILE_LOGGING
               value: "true"
             # Set Felix endpoint to host default action to ACCEPT.
             - name: FELIX_DEFAULTENDPOINTTOHOSTACTION
               value: "ACCEPT"
-            # Disable IPv6 on Kubernetes.
+            # Enable IPv6 on Kubernetes only in a dual-stack cluster.
             - name: FELIX_IPV6SUPPORT
-              value: "false"
+              value: "{{ if .KubePodsCIDRIPv6 }}true{{ else }}false{{ end }}"
             # Set Felix logging to "info"
             - name: FELIX_LOGSEVERITYSCREEN
               value: "info"
@@ -4057,8 +4069,13 @@ metadata:
 
 // GenerateCalicoFilesNew is used to generate calico mainfests.
 func GenerateCalicoFilesNew(mgr *manager.Manager) (string, error) {
+	podsCIDR, podsCIDRIPv6 := mgr.Cluster.Network.KubePodsCIDR, ""
+	if mgr.Cluster.Network.DualStack() {
+		podsCIDR, podsCIDRIPv6 = mgr.Cluster.Network.PodsCIDRIPv4(), mgr.Cluster.Network.PodsCIDRIPv6()
+	}
 	return util.Render(calicoTemplNew, util.Data{
-		"KubePodsCIDR":            mgr.Cluster.Network.KubePodsCIDR,
+		"KubePodsCIDR":            podsCIDR,
+		"KubePodsCIDRIPv6":        podsCIDRIPv6,
 		"CalicoCniImage":          preinstall.GetImage(mgr, "calico-cni").ImageName(),
 		"CalicoNodeImage":         preinstall.GetImage(mgr, "calico-node").ImageName(),
 		"CalicoFlexvolImage":      preinstall.GetImage(mgr, "calico-flexvol").ImageName(),
@@ -69,7 +69,7 @@ data:
 
   # Enable IPv6 addressing. If enabled, all endpoints are allocated an IPv6
   # address.
-  enable-ipv6: "false"
+  enable-ipv6: "{{ if .KubePodsCIDRIPv6 }}true{{ else }}false{{ end }}"
   enable-bpf-clock-probe: "true"
 
   # If you want cilium monitor to aggregate tracing for packets, set this level
@@ -141,7 +141,7 @@ data:
   enable-auto-protect-node-port-range: "true"
   enable-session-affinity: "true"
   k8s-require-ipv4-pod-cidr: "true"
-  k8s-require-ipv6-pod-cidr: "false"
+  k8s-require-ipv6-pod-cidr: "{{ if .KubePodsCIDRIPv6 }}true{{ else }}false{{ end }}"
   enable-endpoint-health-checking: "true"
   enable-well-known-identities: "false"
   enable-remote-node-identity: "true"
@@ -149,6 +149,10 @@ data:
   ipam: "cluster-pool"
   cluster-pool-ipv4-cidr: "{{ .KubePodsCIDR }}"
   cluster-pool-ipv4-mask-size: "{{ .NodeCidrMaskSize }}"
+  {{- if .KubePodsCIDRIPv6 }}
+  cluster-pool-ipv6-cidr: "{{ .KubePodsCIDRIPv6 }}"
+  cluster-pool-ipv6-mask-size: "{{ .NodeCidrMaskSizeIPv6 }}"
+  {{- end }}
   disable-cnp-status-updates: "true"
 ---
 # Source: cilium/charts/agent/templates/clusterrole.yaml
@@ -691,9 +695,15 @@ spec:
     `)))
 
 func GenerateCiliumFiles(mgr *manager.Manager) (string, error) {
+	podsCIDR, podsCIDRIPv6 := mgr.Cluster.Network.KubePodsCIDR, ""
+	if mgr.Cluster.Network.DualStack() {
+		podsCIDR, podsCIDRIPv6 = mgr.Cluster.Network.PodsCIDRIPv4(), mgr.Cluster.Network.PodsCIDRIPv6()
+	}
 	return util.Render(ciliumTempl, util.Data{
-		"KubePodsCIDR":         mgr.Cluster.Network.KubePodsCIDR,
+		"KubePodsCIDR":         podsCIDR,
+		"KubePodsCIDRIPv6":     podsCIDRIPv6,
 		"NodeCidrMaskSize":     mgr.Cluster.Kubernetes.NodeCidrMaskSize,
+		"NodeCidrMaskSizeIPv6": mgr.Cluster.Kubernetes.NodeCidrMaskSizeIPv6,
 		"CiliumImage":          preinstall.GetImage(mgr, "cilium").ImageName(),
 		"OperatorGenericImage": preinstall.GetImage(mgr, "operator-generic").ImageName(),
 	})
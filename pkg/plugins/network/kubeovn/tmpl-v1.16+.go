@@ -20,8 +20,6 @@ import (
 	"fmt"
 	"text/template"
 
-	"github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
-
 	"github.com/kubesphere/kubekey/pkg/cluster/preinstall"
 	"github.com/kubesphere/kubekey/pkg/util"
 	"github.com/kubesphere/kubekey/pkg/util/manager"
@@ -1462,7 +1460,7 @@ func GenerateKubeovnFilesNew(mgr *manager.Manager) (string, error) {
 		"Iface":               mgr.Cluster.Network.Kubeovn.Iface,
 		"DpdkMode":            mgr.Cluster.Network.Kubeovn.DpdkMode,
 		"DpdkVersion":         mgr.Cluster.Network.Kubeovn.DpdkVersion,
-		"OvnVersion":          v1alpha1.DefaultKubeovnVersion,
+		"OvnVersion":          preinstall.GetImage(mgr, "kubeovn").Tag,
 		"EnableSSL":           mgr.Cluster.Network.Kubeovn.EnableSSL,
 		"EnableMirror":        mgr.Cluster.Network.Kubeovn.EnableMirror,
 		"HwOffload":           mgr.Cluster.Network.Kubeovn.HwOffload,
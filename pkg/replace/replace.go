@@ -0,0 +1,141 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replace
+
+import (
+	"fmt"
+	"time"
+
+	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+	"github.com/kubesphere/kubekey/pkg/add"
+	"github.com/kubesphere/kubekey/pkg/config"
+	"github.com/kubesphere/kubekey/pkg/delete"
+	"github.com/kubesphere/kubekey/pkg/util/executor"
+	"github.com/kubesphere/kubekey/pkg/util/manager"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+const nodeReadyTimeout = 5 * time.Minute
+
+// ReplaceNode is used to replace a node, which is usually failed, with a new host.
+// The old node is deleted from the cluster even if it is unreachable, then the new host is added with the same roles, kubelet pools, labels and taints.
+// The new host is given in the same form as the hosts of the config, the fields which are not given are inherited from the old node, except the addresses.
+func ReplaceNode(clusterCfgSources *config.ClusterCfgSources, logger *log.Logger, verbose bool, oldName, newHostSpec string, drainTimeout time.Duration, skipCheck, skipPullImages, updateConfig bool) error {
+	cfg, objName, err := config.ParseClusterCfg(clusterCfgSources, "", "", false, logger)
+	if err != nil {
+		return errors.Wrap(err, "Failed to download cluster config")
+	}
+
+	newHost, err := newHostCfg(&cfg.Spec, oldName, newHostSpec)
+	if err != nil {
+		return err
+	}
+	newSpec := cfg.Spec.DeepCopy()
+	config.ReplaceNodeInClusterSpec(newSpec, oldName, newHost)
+
+	if err := delete.ExecuteDeleteNodes(executor.NewExecutor(&cfg.Spec, objName, logger, "", verbose, false, true, false, false, nil), []string{oldName}, drainTimeout); err != nil {
+		return err
+	}
+	if err := add.Execute(executor.NewExecutor(newSpec, objName, logger, "", verbose, skipCheck, skipPullImages, false, false, nil)); err != nil {
+		return err
+	}
+	if err := ExecuteWaitForNode(executor.NewExecutor(newSpec, objName, logger, "", verbose, false, true, false, false, nil), newHost.Name); err != nil {
+		return err
+	}
+
+	if !updateConfig {
+		fmt.Printf("Please replace %s with %s in the hosts, the role groups and the kubelet pools of the config, or run with --update-config\n", oldName, newHost.Name)
+		return nil
+	}
+	return config.ReplaceNodeInCfgFiles(clusterCfgSources.Files, oldName, newHost)
+}
+
+// newHostCfg parses the new host, and fills in the fields which are not given with the ones of the old node.
+func newHostCfg(spec *kubekeyapiv1alpha1.ClusterSpec, oldName, newHostSpec string) (kubekeyapiv1alpha1.HostCfg, error) {
+	newHost := kubekeyapiv1alpha1.HostCfg{}
+	if err := yaml.Unmarshal([]byte(newHostSpec), &newHost); err != nil {
+		return newHost, errors.Wrap(err, "Failed to parse the new host")
+	}
+	if newHost.Name == "" || newHost.Address == "" {
+		return newHost, errors.New("The name and the address of the new host are required")
+	}
+
+	var oldHost *kubekeyapiv1alpha1.HostCfg
+	for i := range spec.Hosts {
+		switch spec.Hosts[i].Name {
+		case oldName:
+			oldHost = &spec.Hosts[i]
+		case newHost.Name:
+			return newHost, errors.New(fmt.Sprintf("Host %s already exists in the cluster config", newHost.Name))
+		}
+	}
+	if oldHost == nil {
+		return newHost, errors.New(fmt.Sprintf("Node %s is not found in the cluster config", oldName))
+	}
+
+	if newHost.InternalAddress == "" {
+		newHost.InternalAddress = newHost.Address
+	}
+	if newHost.Port == 0 {
+		newHost.Port = oldHost.Port
+	}
+	if newHost.User == "" {
+		newHost.User = oldHost.User
+	}
+	if newHost.Password == "" && newHost.PrivateKey == "" && newHost.PrivateKeyPath == "" {
+		newHost.Password, newHost.PrivateKey, newHost.PrivateKeyPath = oldHost.Password, oldHost.PrivateKey, oldHost.PrivateKeyPath
+	}
+	if newHost.Arch == "" {
+		newHost.Arch = oldHost.Arch
+	}
+	if newHost.Labels == nil {
+		newHost.Labels = oldHost.Labels
+	}
+	if newHost.Taints == nil {
+		newHost.Taints = oldHost.Taints
+	}
+	if newHost.Kubelet == nil {
+		newHost.Kubelet = oldHost.Kubelet
+	}
+	return newHost, nil
+}
+
+func ExecuteWaitForNode(executor *executor.Executor, nodeName string) error {
+	mgr, err := executor.CreateManager()
+	if err != nil {
+		return err
+	}
+
+	for _, node := range mgr.K8sNodes {
+		if node.Name == nodeName {
+			mgr.Logger.Infof("Waiting for node %s to be ready ...\n", nodeName)
+			return mgr.RunTaskOnNodes(mgr.MasterNodes[:1], func(mgr *manager.Manager, _ *kubekeyapiv1alpha1.HostCfg) error {
+				return waitForNodeReady(mgr, nodeName)
+			}, false)
+		}
+	}
+	return nil
+}
+
+func waitForNodeReady(mgr *manager.Manager, nodeName string) error {
+	if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf("sudo -E /bin/sh -c \"/usr/local/bin/kubectl wait --for=condition=Ready node/%s --timeout=%s\"", nodeName, nodeReadyTimeout), 0, true); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Node %s is not ready", nodeName))
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rotate
+
+import (
+	"github.com/kubesphere/kubekey/pkg/cluster/kubernetes"
+	"github.com/kubesphere/kubekey/pkg/config"
+	"github.com/kubesphere/kubekey/pkg/util/executor"
+	"github.com/kubesphere/kubekey/pkg/util/manager"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+func RotateEncryptionKey(clusterCfgSources *config.ClusterCfgSources, logger *log.Logger, verbose bool) error {
+	cfg, objName, err := config.ParseClusterCfg(clusterCfgSources, "", "", false, logger)
+	if err != nil {
+		return errors.Wrap(err, "Failed to download cluster config")
+	}
+	return ExecuteRotateEncryptionKey(executor.NewExecutor(&cfg.Spec, objName, logger, "", verbose, false, true, false, false, nil))
+}
+
+func ExecuteRotateEncryptionKey(executor *executor.Executor) error {
+	mgr, err := executor.CreateManager()
+	if err != nil {
+		return err
+	}
+	return ExecRotateEncryptionKeyTasks(mgr)
+}
+
+func ExecRotateEncryptionKeyTasks(mgr *manager.Manager) error {
+	rotateTasks := []manager.Task{
+		{Task: kubernetes.RotateEncryptionKey, ErrMsg: "Failed to rotate encryption key"},
+	}
+	for _, step := range rotateTasks {
+		if err := step.Run(mgr); err != nil {
+			return errors.Wrap(err, step.ErrMsg)
+		}
+	}
+	mgr.Logger.Infoln("Successful.")
+	return nil
+}
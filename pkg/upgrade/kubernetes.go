@@ -4,10 +4,10 @@ import (
 	"encoding/base64"
 	"fmt"
 	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+	"github.com/kubesphere/kubekey/pkg/catalog"
 	"github.com/kubesphere/kubekey/pkg/cluster/kubernetes"
 	"github.com/kubesphere/kubekey/pkg/cluster/kubernetes/tmpl"
 	"github.com/kubesphere/kubekey/pkg/cluster/preinstall"
-	"github.com/kubesphere/kubekey/pkg/files"
 	"github.com/kubesphere/kubekey/pkg/plugins/dns"
 	"github.com/kubesphere/kubekey/pkg/util"
 	"github.com/kubesphere/kubekey/pkg/util/manager"
@@ -15,7 +15,6 @@ import (
 	versionutil "k8s.io/apimachinery/pkg/util/version"
 	"os"
 	"os/exec"
-	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -132,11 +131,20 @@ func upgradeKubeMasters(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg)
 			return errors.Wrap(errors.WithStack(err1), "Failed to generate kubeadm config")
 		}
 
+		upgradeConfigFlag := "--config=/etc/kubernetes/kubeadm-config.yaml "
+		if tmpl.KubeadmAPIVersion(mgr.Cluster.Kubernetes.Version) == tmpl.KubeadmV1beta4 {
+			// "kubeadm upgrade apply" does not accept ClusterConfiguration since v1.31, the configuration is uploaded to the cluster before upgrading.
+			if _, err := mgr.Runner.ExecuteCmd("sudo -E /bin/sh -c \"/usr/local/bin/kubeadm init phase upload-config kubeadm --config=/etc/kubernetes/kubeadm-config.yaml\"", 3, true); err != nil {
+				return errors.Wrap(errors.WithStack(err), "Failed to upload kubeadm config")
+			}
+			upgradeConfigFlag = ""
+		}
+
 		for i := 0; i < 3; i++ {
 			if _, err := mgr.Runner.ExecuteCmd(fmt.Sprintf(
-				"sudo -E /bin/sh -c \"timeout -k 600s 600s /usr/local/bin/kubeadm upgrade apply -y %s --config=/etc/kubernetes/kubeadm-config.yaml "+
+				"sudo -E /bin/sh -c \"timeout -k 600s 600s /usr/local/bin/kubeadm upgrade apply -y %s %s"+
 					"--ignore-preflight-errors=all --allow-experimental-upgrades --allow-release-candidate-upgrades --etcd-upgrade=false --certificate-renewal=true --force\"",
-				mgr.Cluster.Kubernetes.Version),
+				mgr.Cluster.Kubernetes.Version, upgradeConfigFlag),
 				0, false); err != nil {
 				if i == 1 {
 					return errors.Wrap(errors.WithStack(err), fmt.Sprintf("Failed to upgrade master: %s", node.Name))
@@ -156,6 +164,10 @@ func upgradeKubeMasters(mgr *manager.Manager, node *kubekeyapiv1alpha1.HostCfg)
 			return err
 		}
 
+		if err := kubernetes.PatchKubeProxyArgs(mgr); err != nil {
+			return err
+		}
+
 		if _, err := mgr.Runner.ExecuteCmd("sudo -E /bin/sh -c \"systemctl stop kubelet\"", 2, true); err != nil {
 			return err
 		}
@@ -245,22 +257,11 @@ Loop:
 			if nextVersionMinor == versionutil.MustParseSemantic(targetVersionStr).Minor() {
 				nextVersionStr = targetVersionStr
 			} else {
-				nextVersionPatchList := []int{}
-				for supportVersionStr := range files.FileSha256["kubeadm"]["amd64"] {
-					supportVersion, err := versionutil.ParseSemantic(supportVersionStr)
-					if err != nil {
-						return err
-					}
-					if supportVersion.Minor() == nextVersionMinor {
-						nextVersionPatchList = append(nextVersionPatchList, int(supportVersion.Patch()))
-					}
+				latest, ok := catalog.Get().LatestPatchVersion(currentVersion.Major(), nextVersionMinor)
+				if !ok {
+					return errors.New(fmt.Sprintf("No version of kubernetes v%d.%d is found in the catalog", currentVersion.Major(), nextVersionMinor))
 				}
-				sort.Ints(nextVersionPatchList)
-
-				nextVersion := currentVersion.WithMinor(nextVersionMinor)
-				nextVersion = nextVersion.WithPatch(uint(nextVersionPatchList[len(nextVersionPatchList)-1]))
-
-				nextVersionStr = fmt.Sprintf("v%s", nextVersion.String())
+				nextVersionStr = latest
 			}
 
 			mgr.Cluster.Kubernetes.Version = nextVersionStr
@@ -20,6 +20,7 @@ import (
 	"bufio"
 	"fmt"
 	kubekeyapiv1alpha1 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha1"
+	"github.com/kubesphere/kubekey/pkg/catalog"
 	"github.com/kubesphere/kubekey/pkg/cluster/preinstall"
 	"github.com/kubesphere/kubekey/pkg/util/manager"
 	"github.com/mitchellh/mapstructure"
@@ -30,39 +31,6 @@ import (
 	"strings"
 )
 
-var versionCheck = map[string]map[string]map[string]bool{
-	"v3.0.0": {
-		"k8s": {
-			"v1.18": true,
-			"v1.17": true,
-			"v1.16": true,
-			"v1.15": true,
-		},
-		"ks": {
-			"v2.1.1": true,
-		},
-	},
-	"v2.1.1": {
-		"k8s": {
-			"v1.18": true,
-			"v1.17": true,
-			"v1.16": true,
-			"v1.15": true,
-		},
-		"ks": {
-			"v2.1.1": true,
-		},
-	},
-	"other": {
-		"k8s": {
-			"v1.18": true,
-			"v1.17": true,
-			"v1.16": true,
-			"v1.15": true,
-		},
-	},
-}
-
 func GetClusterInfo(mgr *manager.Manager) error {
 	if err := mgr.RunTaskOnAllNodes(preinstall.PrecheckNodes, true); err != nil {
 		return err
@@ -89,34 +57,36 @@ func getClusterInfo(mgr *manager.Manager, _ *kubekeyapiv1alpha1.HostCfg) error {
 		}
 
 		ksVersion, err := mgr.Runner.ExecuteCmd("sudo -E /bin/sh -c \"/usr/local/bin/kubectl get deploy -n  kubesphere-system ks-console -o jsonpath='{.metadata.labels.version}'\"", 1, false)
+		c := catalog.Get()
+		K8sTargetVersion := versionutil.MustParseSemantic(mgr.Cluster.Kubernetes.Version)
+		k8sTargetMinor := fmt.Sprintf("v%v.%v", K8sTargetVersion.Major(), K8sTargetVersion.Minor())
 		if err != nil {
 			if mgr.Cluster.KubeSphere.Enabled {
 				return errors.New("Failed to get kubesphere version")
 			} else {
-				K8sTargetVersion := versionutil.MustParseSemantic(mgr.Cluster.Kubernetes.Version)
-				if _, ok := versionCheck["other"]["k8s"][fmt.Sprintf("v%v.%v", K8sTargetVersion.Major(), K8sTargetVersion.Minor())]; !ok {
-					return errors.New(fmt.Sprintf("does not support running on Kubernetes %s", fmt.Sprintf("v%v.%v", K8sTargetVersion.Major(), K8sTargetVersion.Minor())))
+				if !c.UpgradeSupported(k8sTargetMinor) {
+					return errors.New(fmt.Sprintf("does not support running on Kubernetes %s", k8sTargetMinor))
 				}
 			}
 		} else {
 			if mgr.Cluster.KubeSphere.Enabled {
-				if _, ok := versionCheck[mgr.Cluster.KubeSphere.Version]; !ok {
+				ksRelease, ok := c.KubeSphereRelease(mgr.Cluster.KubeSphere.Version)
+				if !ok {
 					return errors.New(fmt.Sprintf("Unsupported version: %s", mgr.Cluster.KubeSphere.Version))
 				}
-				if _, ok := versionCheck[mgr.Cluster.KubeSphere.Version]["ks"][ksVersion]; !ok {
+				if !ksRelease.UpgradableFrom(ksVersion) {
 					return errors.New(fmt.Sprintf("Unsupported upgrade plan: %s to %s", strings.TrimSpace(ksVersion), mgr.Cluster.KubeSphere.Version))
 				}
-				K8sTargetVersion := versionutil.MustParseSemantic(mgr.Cluster.Kubernetes.Version)
-				if _, ok := versionCheck[mgr.Cluster.KubeSphere.Version]["k8s"][fmt.Sprintf("v%v.%v", K8sTargetVersion.Major(), K8sTargetVersion.Minor())]; !ok {
-					return errors.New(fmt.Sprintf("KubeSphere %s does not support running on Kubernetes %s", mgr.Cluster.KubeSphere.Version, fmt.Sprintf("v%v.%v", K8sTargetVersion.Major(), K8sTargetVersion.Minor())))
+				if !ksRelease.SupportsKubernetes(k8sTargetMinor) {
+					return errors.New(fmt.Sprintf("KubeSphere %s does not support running on Kubernetes %s", mgr.Cluster.KubeSphere.Version, k8sTargetMinor))
 				}
 			} else {
-				if _, ok := versionCheck[ksVersion]; !ok {
+				ksRelease, ok := c.KubeSphereRelease(ksVersion)
+				if !ok {
 					return errors.New(fmt.Sprintf("Unsupported version: %s", ksVersion))
 				}
-				K8sTargetVersion := versionutil.MustParseSemantic(mgr.Cluster.Kubernetes.Version)
-				if _, ok := versionCheck[ksVersion]["k8s"][fmt.Sprintf("v%v.%v", K8sTargetVersion.Major(), K8sTargetVersion.Minor())]; !ok {
-					return errors.New(fmt.Sprintf("KubeSphere %s does not support running on Kubernetes %s", ksVersion, fmt.Sprintf("v%v.%v", K8sTargetVersion.Major(), K8sTargetVersion.Minor())))
+				if !ksRelease.SupportsKubernetes(k8sTargetMinor) {
+					return errors.New(fmt.Sprintf("KubeSphere %s does not support running on Kubernetes %s", ksVersion, k8sTargetMinor))
 				}
 			}
 		}
@@ -18,6 +18,7 @@ package upgrade
 
 import (
 	"fmt"
+	"github.com/kubesphere/kubekey/pkg/cluster/kubernetes"
 	"github.com/kubesphere/kubekey/pkg/cluster/preinstall"
 	"github.com/kubesphere/kubekey/pkg/config"
 	"github.com/kubesphere/kubekey/pkg/kubesphere"
@@ -30,7 +31,7 @@ import (
 	"path/filepath"
 )
 
-func UpgradeCluster(clusterCfgFile, k8sVersion, ksVersion string, logger *log.Logger, ksEnabled, verbose, skipPullImages bool) error {
+func UpgradeCluster(clusterCfgSources *config.ClusterCfgSources, k8sVersion, ksVersion string, logger *log.Logger, ksEnabled, verbose, skipPullImages bool) error {
 	currentDir, err := filepath.Abs(filepath.Dir(os.Args[0]))
 	if err != nil {
 		return errors.Wrap(err, "Failed to get current dir")
@@ -39,7 +40,7 @@ func UpgradeCluster(clusterCfgFile, k8sVersion, ksVersion string, logger *log.Lo
 		return errors.Wrap(err, "Failed to create work dir")
 	}
 
-	cfg, objName, err := config.ParseClusterCfg(clusterCfgFile, k8sVersion, ksVersion, ksEnabled, logger)
+	cfg, objName, err := config.ParseClusterCfg(clusterCfgSources, k8sVersion, ksVersion, ksEnabled, logger)
 	if err != nil {
 		return errors.Wrap(err, "Failed to download cluster config")
 	}
@@ -52,7 +53,16 @@ func ExecTasks(mgr *manager.Manager) error {
 		{Task: GetClusterInfo, ErrMsg: "Failed to get cluster info"},
 		{Task: GetCurrentVersions, ErrMsg: "Failed to get current version"},
 		{Task: preinstall.InitOS, ErrMsg: "Failed to download kube binaries"},
+		{Task: kubernetes.DeployInternalLoadbalancer, ErrMsg: "Failed to deploy the internal load balancer"},
+		{Task: kubernetes.SyncAuditPolicy, ErrMsg: "Failed to sync audit policy"},
+		{Task: kubernetes.SyncEncryptionConfig, ErrMsg: "Failed to sync encryption config"},
+		{Task: kubernetes.SyncAuthenticationConfig, ErrMsg: "Failed to sync authentication config"},
+		{Task: kubernetes.SyncAdmissionConfig, ErrMsg: "Failed to sync admission config"},
+		{Task: kubernetes.DeployPodSecurityPolicy, ErrMsg: "Failed to deploy pod security policies"},
 		{Task: UpgradeKubeCluster, ErrMsg: "Failed to upgrade kube cluster"},
+		{Task: kubernetes.ConfigureKubelet, ErrMsg: "Failed to configure kubelet"},
+		{Task: kubernetes.ConfigureKubeProxy, ErrMsg: "Failed to configure kube-proxy"},
+		{Task: kubernetes.DeployKubeletCsrApprover, ErrMsg: "Failed to deploy kubelet-csr-approver"},
 		{Task: SyncConfiguration, ErrMsg: "Failed to sync configuration"},
 		{Task: kubesphere.DeployKubeSphere, ErrMsg: "Failed to upgrade kubesphere"},
 	}
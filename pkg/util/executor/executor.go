@@ -67,6 +67,7 @@ func (executor *Executor) CreateManager() (*manager.Manager, error) {
 	mgr.MasterNodes = hostGroups.Master
 	mgr.WorkerNodes = hostGroups.Worker
 	mgr.K8sNodes = hostGroups.K8s
+	mgr.LoadBalancerNodes = hostGroups.LoadBalancer
 	mgr.Cluster = defaultCluster
 	mgr.ClusterHosts = GenerateHosts(hostGroups, defaultCluster)
 	mgr.Connector = ssh.NewDialer()
@@ -101,6 +102,9 @@ func GenerateHosts(hostGroups *kubekeyapiv1alpha1.HostGroups, cfg *kubekeyapiv1a
 	for _, host := range cfg.Hosts {
 		if host.Name != "" {
 			hostsList = append(hostsList, fmt.Sprintf("%s  %s.%s %s", host.InternalAddress, host.Name, cfg.Kubernetes.ClusterName, host.Name))
+			if host.InternalIPv6 != "" {
+				hostsList = append(hostsList, fmt.Sprintf("%s  %s.%s %s", host.InternalIPv6, host.Name, cfg.Kubernetes.ClusterName, host.Name))
+			}
 		}
 	}
 
@@ -26,30 +26,31 @@ import (
 
 // Manager defines all the parameters needed for the installation.
 type Manager struct {
-	ObjName        string
-	Cluster        *kubekeyapiv1alpha1.ClusterSpec
-	Logger         log.FieldLogger
-	Connector      *ssh.Dialer
-	Runner         *runner.Runner
-	AllNodes       []kubekeyapiv1alpha1.HostCfg
-	EtcdNodes      []kubekeyapiv1alpha1.HostCfg
-	MasterNodes    []kubekeyapiv1alpha1.HostCfg
-	WorkerNodes    []kubekeyapiv1alpha1.HostCfg
-	K8sNodes       []kubekeyapiv1alpha1.HostCfg
-	EtcdContainer  bool
-	ClusterHosts   []string
-	WorkDir        string
-	KsEnable       bool
-	KsVersion      string
-	Debug          bool
-	SkipCheck      bool
-	SkipPullImages bool
-	SourcesDir     string
-	AddImagesRepo  bool
-	InCluster      bool
-	Kubeconfig     string
-	Conditions     []kubekeyapiv1alpha1.Condition
-	ClientSet      *kubekeyclientset.Clientset
+	ObjName           string
+	Cluster           *kubekeyapiv1alpha1.ClusterSpec
+	Logger            log.FieldLogger
+	Connector         *ssh.Dialer
+	Runner            *runner.Runner
+	AllNodes          []kubekeyapiv1alpha1.HostCfg
+	EtcdNodes         []kubekeyapiv1alpha1.HostCfg
+	MasterNodes       []kubekeyapiv1alpha1.HostCfg
+	WorkerNodes       []kubekeyapiv1alpha1.HostCfg
+	K8sNodes          []kubekeyapiv1alpha1.HostCfg
+	LoadBalancerNodes []kubekeyapiv1alpha1.HostCfg
+	EtcdContainer     bool
+	ClusterHosts      []string
+	WorkDir           string
+	KsEnable          bool
+	KsVersion         string
+	Debug             bool
+	SkipCheck         bool
+	SkipPullImages    bool
+	SourcesDir        string
+	AddImagesRepo     bool
+	InCluster         bool
+	Kubeconfig        string
+	Conditions        []kubekeyapiv1alpha1.Condition
+	ClientSet         *kubekeyclientset.Clientset
 }
 
 // Copy is used to create a copy for Manager.
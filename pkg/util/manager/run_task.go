@@ -190,3 +190,11 @@ func (mgr *Manager) RunTaskOnK8sNodes(task NodeTask, parallel bool) error {
 	}
 	return nil
 }
+
+// RunTaskOnLoadBalancerNodes is used to execute tasks on all load balancer nodes.
+func (mgr *Manager) RunTaskOnLoadBalancerNodes(task NodeTask, parallel bool) error {
+	if err := mgr.RunTaskOnNodes(mgr.LoadBalancerNodes, task, parallel); err != nil {
+		return err
+	}
+	return nil
+}
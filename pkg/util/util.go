@@ -21,6 +21,7 @@ import (
 	"encoding/binary"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"math/big"
 	"net"
 	"os"
 	"os/exec"
@@ -102,6 +103,30 @@ func ParseIp(ip string) []string {
 	return availableIPs
 }
 
+// GetIPFromCIDR returns the IP at the given offset from the network address of an IPv4 or IPv6 CIDR,
+// it is empty if the CIDR is invalid or the offset is out of the CIDR.
+func GetIPFromCIDR(cidr string, offset int64) string {
+	_, ipnet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+	if err != nil {
+		return ""
+	}
+	ip := ipnet.IP.To4()
+	if ip == nil {
+		ip = ipnet.IP.To16()
+	}
+	n := new(big.Int).Add(new(big.Int).SetBytes(ip), big.NewInt(offset))
+	b := n.Bytes()
+	if len(b) > len(ip) {
+		return ""
+	}
+	result := make(net.IP, len(ip))
+	copy(result[len(ip)-len(b):], b)
+	if !ipnet.Contains(result) {
+		return ""
+	}
+	return result.String()
+}
+
 func GetAvailableIPRange(ipStart, ipEnd string) []string {
 	var availableIPs []string
 
@@ -0,0 +1,46 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import "testing"
+
+func TestGetIPFromCIDR(t *testing.T) {
+	tests := []struct {
+		cidr   string
+		offset int64
+		want   string
+	}{
+		{cidr: "10.233.0.0/18", offset: 1, want: "10.233.0.1"},
+		{cidr: "10.233.0.0/18", offset: 10, want: "10.233.0.10"},
+		{cidr: "10.233.0.0/18", offset: 256, want: "10.233.1.0"},
+		{cidr: " 10.233.0.0/18 ", offset: 3, want: "10.233.0.3"},
+		{cidr: "10.233.5.6/18", offset: 1, want: "10.233.0.1"},
+		{cidr: "10.233.0.0/18", offset: 16383, want: "10.233.63.255"},
+		{cidr: "10.233.0.0/18", offset: 16384, want: ""},
+		{cidr: "255.255.255.0/24", offset: 256, want: ""},
+		{cidr: "fd00:10:96::/108", offset: 1, want: "fd00:10:96::1"},
+		{cidr: "fd00:10:96::/108", offset: 10, want: "fd00:10:96::a"},
+		{cidr: "fd00:10:96::/112", offset: 65536, want: ""},
+		{cidr: "10.233.0.0", offset: 1, want: ""},
+		{cidr: "", offset: 1, want: ""},
+	}
+	for _, tt := range tests {
+		if got := GetIPFromCIDR(tt.cidr, tt.offset); got != tt.want {
+			t.Errorf("GetIPFromCIDR(%q, %d) = %q, want %q", tt.cidr, tt.offset, got, tt.want)
+		}
+	}
+}
@@ -1,24 +1,8 @@
 package version
 
-// SupportedK8sVersionList returns the supported list of Kubernetes
+import "github.com/kubesphere/kubekey/pkg/catalog"
+
+// SupportedK8sVersionList returns the supported list of Kubernetes in the catalog
 func SupportedK8sVersionList() []string {
-	return []string{
-		"v1.15.12",
-		"v1.16.8",
-		"v1.16.10",
-		"v1.16.12",
-		"v1.16.13",
-		"v1.17.0",
-		"v1.17.4",
-		"v1.17.5",
-		"v1.17.6",
-		"v1.17.7",
-		"v1.17.8",
-		"v1.17.9",
-		"v1.18.3",
-		"v1.18.5",
-		"v1.18.6",
-		"v1.18.8",
-		"v1.19.0",
-	}
+	return catalog.Get().KubernetesVersions()
 }